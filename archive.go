@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ArchiveReleases saves every release under dir, one directory per release
+// holding release.json and the downloaded assets
+func ArchiveReleases(provider Provider, repoInfo RepoInfo, dir string) error {
+	keeper, ok := provider.(ReleaseKeeper)
+	if !ok {
+		return fmt.Errorf("archiving releases is not supported for %s", provider.Name())
+	}
+
+	releases, err := provider.ListReleases(repoInfo)
+	if err != nil {
+		return err
+	}
+	if len(releases) == 0 {
+		fmt.Println(info.Render("No releases found to archive"))
+		return nil
+	}
+	fmt.Println(info.Render(fmt.Sprintf("Archiving %d releases to %s", len(releases), dir)))
+
+	for _, release := range releases {
+		releaseDir := filepath.Join(dir, archiveDirName(release))
+		if err := os.MkdirAll(releaseDir, 0755); err != nil {
+			return fmt.Errorf("failed to create archive directory: %v", err)
+		}
+
+		saved, err := keeper.SaveRelease(repoInfo, release, releaseDir)
+		if err != nil {
+			return fmt.Errorf("failed to archive release %s: %v", releaseLabel(release), err)
+		}
+
+		if err := writeReleaseJSON(saved, releaseDir); err != nil {
+			return fmt.Errorf("failed to write release %s: %v", releaseLabel(release), err)
+		}
+		fmt.Println(success.Render(fmt.Sprintf("Archived release %s with %d assets", releaseLabel(release), len(saved.Assets))))
+	}
+	return nil
+}
+
+// writeReleaseJSON writes the release.json of a saved release into dir, with
+// asset paths recorded relative to it
+func writeReleaseJSON(saved SavedRelease, dir string) error {
+	saved.Assets = slices.Clone(saved.Assets)
+	for i := range saved.Assets {
+		if saved.Assets[i].Path != "" {
+			saved.Assets[i].Path = filepath.Base(saved.Assets[i].Path)
+		}
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "release.json"), append(data, '\n'), 0644)
+}
+
+// archiveDirName names the archive directory of a release after its tag,
+// falling back to its ID
+func archiveDirName(release Release) string {
+	name := release.TagName
+	if name == "" {
+		name = fmt.Sprintf("%d", release.ID)
+	}
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "." || name == ".." {
+		name = "_" + name
+	}
+	return name
+}
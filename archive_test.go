@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeGitHubReleases serves two releases, the second with an asset whose
+// download answers with assetStatus
+func fakeGitHubReleases(t *testing.T, assetStatus int, next http.Handler) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case !strings.HasPrefix(r.URL.Path, "/api/") && next != nil:
+			next.ServeHTTP(w, r)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo":
+			json.NewEncoder(w).Encode(map[string]string{"default_branch": "main"})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo/releases":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 2, "name": "Second", "tag_name": "v1.2"},
+				{"id": 1, "name": "First", "tag_name": "release/1.1"},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo/releases/1":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 1, "name": "First", "tag_name": "release/1.1", "body": "First notes"})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo/releases/2":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": 2, "name": "Second", "tag_name": "v1.2", "body": "Second notes",
+				"assets": []map[string]interface{}{{"id": 7, "name": "app.tar.gz"}},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo/releases/assets/7":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.WriteHeader(assetStatus)
+			w.Write([]byte("asset content"))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestArchiveReleases(t *testing.T) {
+	server := fakeGitHubReleases(t, http.StatusOK, nil)
+	defer server.Close()
+
+	provider, err := GetProvider(GitHub)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repoInfo := RepoInfo{Provider: GitHub, FullPath: "owner", RepoName: "repo", Token: "token", GitHubURL: server.URL}
+	dir := t.TempDir()
+
+	if err := ArchiveReleases(provider, repoInfo, dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		dir    string
+		body   string
+		assets []string
+	}{
+		{"v1.2", "Second notes", []string{"app.tar.gz"}},
+		{"release_1.1", "First notes", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.dir, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(dir, tc.dir, "release.json"))
+			if err != nil {
+				t.Fatalf("Expected release.json to be written: %v", err)
+			}
+
+			var saved SavedRelease
+			if err := json.Unmarshal(data, &saved); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if saved.Body != tc.body {
+				t.Errorf("Expected body %s, got %s", tc.body, saved.Body)
+			}
+			if len(saved.Metadata) == 0 {
+				t.Error("Expected the raw release metadata to be archived")
+			}
+			if len(saved.Assets) != len(tc.assets) {
+				t.Fatalf("Expected %d assets, got %d", len(tc.assets), len(saved.Assets))
+			}
+			for i, name := range tc.assets {
+				content, err := os.ReadFile(filepath.Join(dir, tc.dir, saved.Assets[i].Path))
+				if err != nil || string(content) != "asset content" {
+					t.Errorf("Expected asset %s to be downloaded, got %q (%v)", name, content, err)
+				}
+			}
+		})
+	}
+}
+
+func TestResetRepoAbortsWhenArchiveFails(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	head := git(t, bare, "rev-parse", "main")
+	server := fakeGitHubReleases(t, http.StatusInternalServerError, gitHTTPBackend(t, bare))
+	defer server.Close()
+
+	repoInfo := RepoInfo{
+		Provider:   GitHub,
+		FullPath:   "owner",
+		RepoName:   "repo",
+		Token:      "token",
+		GitHubURL:  server.URL,
+		ArchiveDir: t.TempDir(),
+	}
+
+	if err := ResetRepo(repoInfo, "fresh start"); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	if current := git(t, bare, "rev-parse", "main"); current != head {
+		t.Errorf("Expected main to be left at %s, got %s", head, current)
+	}
+	if tags := git(t, bare, "tag"); tags != "v1.0\nv1.1\nv1.2" {
+		t.Errorf("Expected tags to be left untouched, got %s", tags)
+	}
+}
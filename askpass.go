@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	askPassEnv         = "GORESETIT_ASKPASS"
+	askPassUsernameEnv = "GORESETIT_ASKPASS_USERNAME"
+	askPassTokenEnv    = "GORESETIT_ASKPASS_TOKEN"
+)
+
+// GitAuth holds the credentials used by git commands that talk to the remote.
+// Over HTTPS they are handed to git through GIT_ASKPASS, pointing back at this
+// binary, so the token never appears in the remote URL, .git/config or the
+// command line. Over SSH only GIT_SSH_COMMAND is set.
+type GitAuth struct {
+	Username   string
+	Token      string
+	SSHCommand string
+}
+
+// gitAuth is set by ResetRepo for the duration of a reset
+var gitAuth GitAuth
+
+// Env returns the environment that makes a single git invocation authenticate
+// with the token instead of any configured credential helper
+func (a GitAuth) Env() ([]string, error) {
+	if a.SSHCommand != "" {
+		return []string{"GIT_SSH_COMMAND=" + a.SSHCommand}, nil
+	}
+	if a.Token == "" {
+		return nil, nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate goresetit executable for GIT_ASKPASS: %v", err)
+	}
+
+	return []string{
+		"GIT_ASKPASS=" + executable,
+		"GIT_TERMINAL_PROMPT=0",
+		// An empty credential.helper resets the list of configured helpers
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=credential.helper",
+		"GIT_CONFIG_VALUE_0=",
+		askPassEnv + "=1",
+		askPassUsernameEnv + "=" + a.Username,
+		askPassTokenEnv + "=" + a.Token,
+	}, nil
+}
+
+// Redact masks the token in text that is about to be shown to the user
+func (a GitAuth) Redact(text string) string {
+	if a.Token == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, a.Token, "********")
+}
+
+// HandleAskPass answers git's username and password prompts when goresetit is
+// invoked as GIT_ASKPASS, and reports whether it did so
+func HandleAskPass() bool {
+	if os.Getenv(askPassEnv) != "1" {
+		return false
+	}
+
+	var prompt string
+	if len(os.Args) > 1 {
+		prompt = os.Args[1]
+	}
+
+	if strings.HasPrefix(prompt, "Username") {
+		fmt.Println(os.Getenv(askPassUsernameEnv))
+	} else {
+		fmt.Println(os.Getenv(askPassTokenEnv))
+	}
+	return true
+}
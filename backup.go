@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// backupRefs are the refs a backup covers. Provider managed refs, such as
+// GitHub pull request heads, are left out since they cannot be pushed back.
+var backupRefs = []string{"refs/heads", "refs/tags", "refs/notes"}
+
+// BackupManifest records the refs of the repository when the bundle was written
+type BackupManifest struct {
+	Repository string      `json:"repository"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Refs       []BackupRef `json:"refs"`
+}
+
+// BackupRef is a ref and the object it pointed at
+type BackupRef struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+// ManifestPath returns the path of the manifest written next to a bundle
+func ManifestPath(bundle string) string {
+	return bundle + ".json"
+}
+
+// BackupRepo mirror-clones remoteURL into workDir and writes its branches,
+// tags and notes to a git bundle, along with a manifest of their SHAs
+func BackupRepo(remoteURL, workDir, bundle string) (*BackupManifest, error) {
+	fmt.Println(info.Render(fmt.Sprintf("Backing up all refs to %s", bundle)))
+
+	mirror, err := mirrorRepo(remoteURL, workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := listBackupRefs(mirror)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("repository has no refs to back up")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(bundle), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %v", err)
+	}
+	if err := RunGitCommandWithOutput("-C", mirror, "bundle", "create", bundle, "--branches", "--tags", "--glob=refs/notes/*"); err != nil {
+		return nil, fmt.Errorf("failed to create bundle: %v", err)
+	}
+	if err := RunGitCommandWithOutput("-C", mirror, "bundle", "verify", "--quiet", bundle); err != nil {
+		return nil, fmt.Errorf("failed to verify bundle: %v", err)
+	}
+
+	manifest := &BackupManifest{Repository: remoteURL, CreatedAt: time.Now().UTC(), Refs: refs}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %v", err)
+	}
+	if err := os.WriteFile(ManifestPath(bundle), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %v", err)
+	}
+
+	fmt.Println(success.Render(fmt.Sprintf("Backed up %d refs to %s", len(refs), bundle)))
+	return manifest, nil
+}
+
+// RestoreRepo force-pushes the branches, tags and notes of a backup bundle
+// back to the remote, after checking them against the manifest when present
+func RestoreRepo(repoInfo RepoInfo, bundle string) error {
+	provider, err := GetProvider(repoInfo.Provider)
+	if err != nil {
+		return err
+	}
+
+	remoteURL, err := RemoteURL(provider, repoInfo)
+	if err != nil {
+		return err
+	}
+
+	bundle, err = filepath.Abs(bundle)
+	if err != nil {
+		return fmt.Errorf("failed to resolve bundle path: %v", err)
+	}
+	if err := resolveSSHPaths(&repoInfo); err != nil {
+		return err
+	}
+
+	gitAuth = NewGitAuth(provider, repoInfo)
+	defer func() { gitAuth = GitAuth{} }()
+
+	tmpPath, err := os.MkdirTemp("", "goresetit-restore")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpPath)
+
+	if err := os.Chdir(tmpPath); err != nil {
+		return fmt.Errorf("failed to change to temporary directory: %v", err)
+	}
+
+	// Cloning the bundle also checks that it is complete
+	restored := filepath.Join(tmpPath, "restore.git")
+	if err := RunGitCommandWithOutput("clone", "--mirror", bundle, restored); err != nil {
+		return fmt.Errorf("failed to read bundle: %v", err)
+	}
+
+	refs, err := listBackupRefs(restored)
+	if err != nil {
+		return err
+	}
+	if err := checkManifest(bundle, refs); err != nil {
+		return err
+	}
+
+	refspecs := backupRefspecs()
+	if repoInfo.DryRun {
+		fmt.Println(info.Render(fmt.Sprintf("Would restore %d refs:", len(refs))))
+		for _, ref := range refs {
+			fmt.Println(info.Render(fmt.Sprintf("- %s %s", ref.SHA, ref.Ref)))
+		}
+		fmt.Println(info.Render(fmt.Sprintf("Would execute: git push --force %s %s", remoteURL, strings.Join(refspecs, " "))))
+		return nil
+	}
+
+	fmt.Println(info.Render(fmt.Sprintf("Restoring %d refs to %s", len(refs), remoteURL)))
+	pushArgs := append([]string{"-C", restored, "push", "--force", remoteURL}, refspecs...)
+	if err := RunAuthenticatedGitCommand(pushArgs...); err != nil {
+		return fmt.Errorf("failed to push refs: %v", err)
+	}
+	fmt.Println(success.Render(fmt.Sprintf("Restored %d refs", len(refs))))
+	fmt.Println(warning.Render("Releases are not part of the bundle and were not restored"))
+	return nil
+}
+
+// BackupToRemote mirror-clones remoteURL into workDir, force-pushes its
+// branches, tags and notes to backupURL, then checks every ref SHA on the
+// backup. The backup remote authenticates with the user's own git setup, the
+// reset token is never sent to it. Refs that only exist on the backup are kept.
+// A dry run only lists what would be pushed.
+func BackupToRemote(remoteURL, workDir, backupURL string, dryRun bool) error {
+	fmt.Println(info.Render(fmt.Sprintf("Backing up all refs to %s", backupURL)))
+
+	mirror, err := mirrorRepo(remoteURL, workDir)
+	if err != nil {
+		return err
+	}
+
+	refs, err := listBackupRefs(mirror)
+	if err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		return fmt.Errorf("repository has no refs to back up")
+	}
+
+	pushArgs := append([]string{"-C", mirror, "push", "--force", backupURL}, backupRefspecs()...)
+	if dryRun {
+		fmt.Println(info.Render(fmt.Sprintf("Would back up %d refs:", len(refs))))
+		for _, ref := range refs {
+			fmt.Println(info.Render(fmt.Sprintf("- %s %s", ref.SHA, ref.Ref)))
+		}
+		fmt.Println(info.Render(fmt.Sprintf("Would execute: git %s", strings.Join(pushArgs[2:], " "))))
+		return nil
+	}
+	if err := RunGitCommandWithOutput(pushArgs...); err != nil {
+		return fmt.Errorf("failed to push to backup remote: %v", err)
+	}
+
+	output, err := GitCommandOutput("ls-remote", backupURL)
+	if err != nil {
+		return fmt.Errorf("failed to list backup remote refs: %v", err)
+	}
+	found := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		if sha, ref, ok := strings.Cut(line, "\t"); ok {
+			found[ref] = sha
+		}
+	}
+	for _, ref := range refs {
+		if found[ref.Ref] != ref.SHA {
+			return fmt.Errorf("backup remote does not match: %s should be %s, found '%s'", ref.Ref, ref.SHA, found[ref.Ref])
+		}
+	}
+
+	fmt.Println(success.Render(fmt.Sprintf("Verified %d refs on %s", len(refs), backupURL)))
+	return nil
+}
+
+// mirrorRepo mirror-clones remoteURL into workDir, reusing an existing mirror
+// so that several backups only clone once
+func mirrorRepo(remoteURL, workDir string) (string, error) {
+	mirror := mirrorPath(workDir)
+	if _, err := os.Stat(mirror); err == nil {
+		return mirror, nil
+	}
+	if err := RunAuthenticatedGitCommand("clone", "--mirror", remoteURL, mirror); err != nil {
+		return "", fmt.Errorf("failed to mirror repository: %v", err)
+	}
+	return mirror, nil
+}
+
+// mirrorPath returns where mirrorRepo clones the repository in workDir
+func mirrorPath(workDir string) string {
+	return filepath.Join(workDir, "mirror.git")
+}
+
+// backupRefspecs maps every backed up ref onto the same name on the remote
+func backupRefspecs() []string {
+	var refspecs []string
+	for _, prefix := range backupRefs {
+		refspecs = append(refspecs, fmt.Sprintf("%s/*:%s/*", prefix, prefix))
+	}
+	return refspecs
+}
+
+// listBackupRefs lists the refs of a repository that a backup covers
+func listBackupRefs(gitDir string) ([]BackupRef, error) {
+	args := append([]string{"-C", gitDir, "for-each-ref", "--format=%(objectname) %(refname)"}, backupRefs...)
+	output, err := GitCommandOutput(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refs: %v", err)
+	}
+
+	refs := []BackupRef{}
+	for _, line := range strings.Split(output, "\n") {
+		if sha, ref, ok := strings.Cut(line, " "); ok {
+			refs = append(refs, BackupRef{Ref: ref, SHA: sha})
+		}
+	}
+	return refs, nil
+}
+
+// checkManifest makes sure the refs read from a bundle are the ones its
+// manifest recorded. Bundles without a manifest are accepted as is.
+func checkManifest(bundle string, refs []BackupRef) error {
+	data, err := os.ReadFile(ManifestPath(bundle))
+	if os.IsNotExist(err) {
+		fmt.Println(warning.Render(fmt.Sprintf("Warning: No manifest found at %s, restoring the bundle as is", ManifestPath(bundle))))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %v", err)
+	}
+
+	var manifest BackupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse manifest: %v", err)
+	}
+
+	found := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		found[ref.Ref] = ref.SHA
+	}
+	for _, ref := range manifest.Refs {
+		if found[ref.Ref] != ref.SHA {
+			return fmt.Errorf("bundle does not match its manifest: %s should be %s, found '%s'", ref.Ref, ref.SHA, found[ref.Ref])
+		}
+	}
+	if len(manifest.Refs) != len(refs) {
+		return fmt.Errorf("bundle does not match its manifest: expected %d refs, found %d", len(manifest.Refs), len(refs))
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	work := filepath.Join(t.TempDir(), "work")
+	git(t, filepath.Dir(work), "clone", bare, work)
+	git(t, work, "notes", "add", "-m", "reviewed")
+	git(t, work, "push", "origin", "refs/notes/*")
+
+	originalRefs := git(t, bare, "for-each-ref", "--format=%(objectname) %(refname)")
+	bundle := filepath.Join(t.TempDir(), "backup", "repo.bundle")
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+		Backup:   bundle,
+	}
+
+	if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count := git(t, bare, "rev-list", "--count", "main"); count != "1" {
+		t.Fatalf("Expected the reset to go through, got %s commits", count)
+	}
+
+	data, err := os.ReadFile(ManifestPath(bundle))
+	if err != nil {
+		t.Fatalf("Expected a manifest next to the bundle: %v", err)
+	}
+	var manifest BackupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var manifestRefs []string
+	for _, ref := range manifest.Refs {
+		manifestRefs = append(manifestRefs, ref.SHA+" "+ref.Ref)
+	}
+	if strings.Join(manifestRefs, "\n") != originalRefs {
+		t.Errorf("Expected manifest refs\n%s\ngot\n%s", originalRefs, strings.Join(manifestRefs, "\n"))
+	}
+
+	if err := RestoreRepo(repoInfo, bundle); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if refs := git(t, bare, "for-each-ref", "--format=%(objectname) %(refname)"); refs != originalRefs {
+		t.Errorf("Expected refs to be restored to\n%s\ngot\n%s", originalRefs, refs)
+	}
+}
+
+func TestRestoreRejectsManifestMismatch(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	bundle := filepath.Join(t.TempDir(), "repo.bundle")
+	git(t, bare, "bundle", "create", bundle, "--all")
+
+	manifest := BackupManifest{Refs: []BackupRef{{Ref: "refs/heads/main", SHA: strings.Repeat("0", 40)}}}
+	data, _ := json.Marshal(manifest)
+	if err := os.WriteFile(ManifestPath(bundle), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+	}
+
+	if err := RestoreRepo(repoInfo, bundle); err == nil {
+		t.Error("Expected error but got none")
+	}
+}
+
+func TestResetRepoBackupRemote(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rejectHook  bool
+		relative    bool
+		dryRun      bool
+		expectError bool
+	}{
+		{name: "Backup verified", rejectHook: false},
+		{name: "Backup rejected", rejectHook: true, expectError: true},
+		{name: "Relative backup path", relative: true},
+		{name: "Dry run", dryRun: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cleanup := setupTestEnv(t)
+			defer cleanup()
+
+			bare := setupBareRepo(t, "main")
+			originalRefs := git(t, bare, "for-each-ref", "--format=%(objectname) %(refname)")
+
+			backup := filepath.Join(t.TempDir(), "backup.git")
+			git(t, filepath.Dir(backup), "init", "--bare", backup)
+			if tc.rejectHook {
+				hook := "#!/bin/sh\ncase \"$1\" in refs/tags/*) exit 1;; esac\n"
+				if err := os.WriteFile(filepath.Join(backup, "hooks", "update"), []byte(hook), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			backupRemote := backup
+			if tc.relative {
+				cwd, err := os.Getwd()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if backupRemote, err = filepath.Rel(cwd, backup); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			repoInfo := RepoInfo{
+				Provider:     GitRemote,
+				FullPath:     bare,
+				RepoName:     "repo",
+				BackupRemote: backupRemote,
+				DryRun:       tc.dryRun,
+			}
+
+			err := ResetRepo(repoInfo, "fresh start")
+
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				if refs := git(t, bare, "for-each-ref", "--format=%(objectname) %(refname)"); refs != originalRefs {
+					t.Errorf("Expected the source to be left untouched, got\n%s", refs)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.dryRun {
+				if refs := git(t, backup, "for-each-ref"); refs != "" {
+					t.Errorf("Expected nothing to be pushed to the backup during a dry run, got\n%s", refs)
+				}
+				return
+			}
+			if refs := git(t, backup, "for-each-ref", "--format=%(objectname) %(refname)"); refs != originalRefs {
+				t.Errorf("Expected backup refs\n%s\ngot\n%s", originalRefs, refs)
+			}
+		})
+	}
+}
+
+func TestResetRepoBackupThenConcurrentPush(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	work := filepath.Join(t.TempDir(), "work")
+	git(t, filepath.Dir(work), "clone", bare, work)
+	originalTags := git(t, bare, "tag")
+
+	// A teammate pushes right after the backup mirror was cloned
+	hooks := t.TempDir()
+	hook := "#!/bin/sh\n[ \"$1\" = committed ] || exit 0\n" +
+		"case \"$GIT_DIR\" in *mirror.git) ;; *) exit 0;; esac\n" +
+		"unset GIT_DIR GIT_INDEX_FILE GIT_WORK_TREE GIT_PREFIX\n" +
+		"git -C " + work + " -c core.hooksPath=/dev/null commit -q --allow-empty -m concurrent &&\n" +
+		"git -C " + work + " -c core.hooksPath=/dev/null push -q origin main\n"
+	if err := os.WriteFile(filepath.Join(hooks, "reference-transaction"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(t.TempDir(), "gitconfig")
+	if err := os.WriteFile(config, []byte("[core]\n\thooksPath = "+hooks+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", config)
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+		Backup:   filepath.Join(t.TempDir(), "repo.bundle"),
+	}
+
+	err := ResetRepo(repoInfo, "fresh start")
+	if err == nil || !strings.Contains(err.Error(), "after the backup was taken") {
+		t.Fatalf("Expected the reset to stop when the branch moved after the backup, got %v", err)
+	}
+
+	if message := git(t, bare, "log", "-1", "--format=%s", "main"); message != "concurrent" {
+		t.Errorf("Expected the concurrent commit to survive, got '%s'", message)
+	}
+	if tags := git(t, bare, "tag"); tags != originalTags {
+		t.Errorf("Expected tags to be left untouched, got %s", tags)
+	}
+}
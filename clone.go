@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CloneStrategies lists the accepted --clone-strategy values, the default first
+func CloneStrategies() []string {
+	return []string{string(CloneBlobless), string(CloneShallow), string(CloneFull)}
+}
+
+// cloneArgs returns the git clone arguments of a strategy. Every strategy
+// clones a bare repository: the new commit is built from tree objects, so a
+// working tree would only cost time and disk space.
+func cloneArgs(strategy CloneStrategy, cloneURL, dir, branch string) ([]string, error) {
+	args := []string{"clone", "--bare"}
+	switch strategy {
+	case CloneBlobless, "":
+		// Trees are all the reset needs, blobs are fetched on demand
+		args = append(args, "--filter=blob:none", "--single-branch")
+	case CloneShallow:
+		args = append(args, "--depth", "1")
+	case CloneFull:
+	default:
+		return nil, fmt.Errorf("unknown clone strategy '%s', use one of: %s", strategy, strings.Join(CloneStrategies(), ", "))
+	}
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	return append(args, cloneURL, dir), nil
+}
+
+// CloneRepo clones the repository into dir with the given strategy, and
+// reports how long it took and how much was transferred. An empty branch
+// clones the default branch of the remote.
+func CloneRepo(cloneURL, dir, branch string, strategy CloneStrategy) error {
+	args, err := cloneArgs(strategy, cloneURL, dir, branch)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(info.Render(fmt.Sprintf("Cloning repository: git %s", strings.Join(args, " "))))
+	start := time.Now()
+	if err := RunAuthenticatedGitCommand(args...); err != nil {
+		return fmt.Errorf("failed to clone repository: %v", err)
+	}
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	size, err := objectsSize(dir)
+	if err != nil {
+		fmt.Println(warning.Render(fmt.Sprintf("Warning: Failed to measure the clone size: %v", err)))
+		fmt.Println(info.Render(fmt.Sprintf("Cloned in %s", elapsed)))
+		return nil
+	}
+	fmt.Println(info.Render(fmt.Sprintf("Cloned in %s, %s transferred", elapsed, formatBytes(size))))
+	return nil
+}
+
+// FetchTags brings the tags that do not point into the cloned branch into a
+// partial clone, with the same filter, so that they can be read and recreated
+func FetchTags(strategy CloneStrategy) error {
+	if strategy == CloneFull {
+		return nil
+	}
+
+	args := append(append([]string{"fetch"}, fetchArgs(strategy)...), "origin", "+refs/tags/*:refs/tags/*")
+	if err := RunAuthenticatedGitCommand(args...); err != nil {
+		return fmt.Errorf("failed to fetch tags: %v", err)
+	}
+	return nil
+}
+
+// ResolveFrom returns the commit a --from ref or SHA points to. Refs that did
+// not come down with the clone, such as other branches, are fetched.
+func ResolveFrom(ref string, strategy CloneStrategy) (string, error) {
+	if commit, err := GitCommandOutput("rev-parse", "--verify", "--quiet", ref+"^{commit}"); err == nil {
+		return commit, nil
+	}
+
+	args := append(append([]string{"fetch"}, fetchArgs(strategy)...), "origin", ref)
+	if err := RunAuthenticatedGitCommand(args...); err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %v", ref, err)
+	}
+	commit, err := GitCommandOutput("rev-parse", "--verify", "FETCH_HEAD^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("%s does not point to a commit: %v", ref, err)
+	}
+	return commit, nil
+}
+
+// fetchArgs returns the git fetch options that keep a fetch as partial as the clone
+func fetchArgs(strategy CloneStrategy) []string {
+	switch strategy {
+	case CloneBlobless, "":
+		return []string{"--filter=blob:none"}
+	case CloneShallow:
+		return []string{"--depth", "1"}
+	}
+	return nil
+}
+
+// objectsSize returns the size of the object database of a repository, which
+// for a fresh clone is the size of the packs received
+func objectsSize(dir string) (int64, error) {
+	output, err := GitCommandOutput("-C", dir, "count-objects", "-v")
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok || (key != "size" && key != "size-pack") {
+			continue
+		}
+		kib, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %s: %v", key, err)
+		}
+		size += kib * 1024
+	}
+	return size, nil
+}
+
+// formatBytes renders a byte count with a binary unit
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, exp := float64(size)/unit, 0
+	for value >= unit && exp < 3 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[exp])
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResetRepoCloneStrategies(t *testing.T) {
+	testCases := []struct {
+		strategy     CloneStrategy
+		expectedArgs string
+	}{
+		{"", "clone --bare --filter=blob:none --single-branch --branch main"},
+		{CloneBlobless, "clone --bare --filter=blob:none --single-branch --branch main"},
+		{CloneShallow, "clone --bare --depth 1 --branch main"},
+		{CloneFull, "clone --bare --branch main"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.strategy), func(t *testing.T) {
+			cleanup := setupTestEnv(t)
+			defer cleanup()
+
+			bare := setupBareRepo(t, "main")
+			git(t, bare, "config", "uploadpack.allowFilter", "true")
+			originalTree := git(t, bare, "rev-parse", "main^{tree}")
+
+			// The highest tag lives on another branch, out of reach of a single-branch clone
+			work := filepath.Join(t.TempDir(), "work")
+			git(t, filepath.Dir(work), "clone", bare, work)
+			git(t, work, "checkout", "-b", "next")
+			git(t, work, "commit", "--allow-empty", "-m", "next")
+			git(t, work, "tag", "-a", "v2.0.0", "-m", "Version 2.0.0")
+			git(t, work, "push", "origin", "next", "v2.0.0")
+
+			repoInfo := RepoInfo{
+				Provider:      GitRemote,
+				FullPath:      "file://" + bare,
+				RepoName:      "repo",
+				CloneStrategy: tc.strategy,
+				Retag:         []string{"latest"},
+			}
+
+			output := captureOutput(func() {
+				if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			})
+
+			if !strings.Contains(output, tc.expectedArgs) {
+				t.Errorf("Expected the clone to run git %s, got %s", tc.expectedArgs, output)
+			}
+			if !strings.Contains(output, "Cloned in") || !strings.Contains(output, "transferred") {
+				t.Errorf("Expected the clone time and size to be reported, got %s", output)
+			}
+			if tree := git(t, bare, "rev-parse", "main^{tree}"); tree != originalTree {
+				t.Errorf("Expected tree %s to be preserved, got %s", originalTree, tree)
+			}
+			if count := git(t, bare, "rev-list", "--count", "main"); count != "1" {
+				t.Errorf("Expected a single commit on main, got %s", count)
+			}
+			if target, head := git(t, bare, "rev-parse", "v2.0.0^{commit}"), git(t, bare, "rev-parse", "main"); target != head {
+				t.Errorf("Expected v2.0.0 to be recreated on the new root commit %s, got %s", head, target)
+			}
+		})
+	}
+}
+
+func TestResetRepoFrom(t *testing.T) {
+	testCases := []struct {
+		name string
+		from func(bare string) string
+	}{
+		{"Tag", func(bare string) string { return "v1.0" }},
+		{"Branch outside the clone", func(bare string) string { return "old" }},
+		{"SHA", func(bare string) string { return git(t, bare, "rev-parse", "main~1") }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cleanup := setupTestEnv(t)
+			defer cleanup()
+
+			bare := setupBareRepo(t, "main")
+			git(t, bare, "config", "uploadpack.allowFilter", "true")
+			git(t, bare, "branch", "old", "main~2")
+			from := tc.from(bare)
+			expectedTree := git(t, bare, "rev-parse", from+"^{tree}")
+
+			repoInfo := RepoInfo{
+				Provider: GitRemote,
+				FullPath: "file://" + bare,
+				RepoName: "repo",
+				From:     from,
+			}
+
+			if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tree := git(t, bare, "rev-parse", "main^{tree}"); tree != expectedTree {
+				t.Errorf("Expected main to have the tree of %s %s, got %s", from, expectedTree, tree)
+			}
+			if count := git(t, bare, "rev-list", "--count", "main"); count != "1" {
+				t.Errorf("Expected a single commit on main, got %s", count)
+			}
+		})
+	}
+}
+
+func TestResetRepoFromDryRun(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	work := filepath.Join(t.TempDir(), "work")
+	git(t, filepath.Dir(work), "clone", bare, work)
+	if err := os.WriteFile(filepath.Join(work, "extra.txt"), []byte("extra"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, work, "add", "extra.txt")
+	git(t, work, "commit", "-m", "extra")
+	git(t, work, "push", "origin", "main")
+	originalHead := git(t, bare, "rev-parse", "main")
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+		From:     "v1.0",
+		DryRun:   true,
+	}
+
+	output := captureOutput(func() {
+		if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	for _, expected := range []string{"Would change 2 files", "Removed: extra.txt", "Modified: file.txt"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain '%s', got %s", expected, output)
+		}
+	}
+	if head := git(t, bare, "rev-parse", "main"); head != originalHead {
+		t.Errorf("Expected main to be left at %s during a dry run, got %s", originalHead, head)
+	}
+}
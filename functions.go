@@ -1,30 +1,19 @@
 package main
 
 import (
-	"context"
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
-
-	"github.com/google/go-github/v38/github"
-	"github.com/xanzy/go-gitlab"
-	"golang.org/x/oauth2"
 )
 
-// For mocking in tests
-var (
-	newGitHubClient = func(token string) *github.Client {
-		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-		tc := oauth2.NewClient(context.Background(), ts)
-		return github.NewClient(tc)
-	}
-
-	newGitLabClient = func(token, baseURL string) (*gitlab.Client, error) {
-		return gitlab.NewClient(token, gitlab.WithBaseURL(baseURL))
-	}
-)
+// tagPushBatchSize caps the number of tags deleted by a single git push
+const tagPushBatchSize = 100
 
 type CommandError struct {
 	Command string
@@ -40,22 +29,151 @@ func (e *CommandError) Error() string {
 }
 
 func RunGitCommandWithOutput(args ...string) error {
+	return execGitCommand(nil, args...)
+}
+
+// RunAuthenticatedGitCommand runs a git command that talks to the remote,
+// authenticating with the credentials of the reset in progress
+func RunAuthenticatedGitCommand(args ...string) error {
+	env, err := gitAuth.Env()
+	if err != nil {
+		return err
+	}
+	return execGitCommand(env, args...)
+}
+
+func execGitCommand(env []string, args ...string) error {
 	cmd := exec.Command("git", args...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return &CommandError{
 			Command: "git " + strings.Join(args, " "),
-			Output:  string(output),
+			Output:  gitAuth.Redact(string(output)),
 			Err:     err,
 		}
 	}
 	if len(output) > 0 {
-		fmt.Print(string(output))
+		fmt.Print(gitAuth.Redact(string(output)))
 	}
 	return nil
 }
 
+// GitCommandOutput runs a local git command and returns its trimmed standard output
+func GitCommandOutput(args ...string) (string, error) {
+	return gitCommandOutput(nil, args...)
+}
+
+// gitCommandOutput returns the trimmed standard output of a git command, even
+// when it fails, so that partial results such as push statuses can be parsed
+func gitCommandOutput(env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return strings.TrimSpace(string(output)), &CommandError{
+			Command: "git " + strings.Join(args, " "),
+			Output:  gitAuth.Redact(stderr.String()),
+			Err:     err,
+		}
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DetectBranch returns the branch to reset: the --branch override, the default
+// branch reported by the provider API, or the HEAD of the remote
+func DetectBranch(provider Provider, repoInfo RepoInfo, remoteURL string) (string, error) {
+	if repoInfo.Branch != "" {
+		return repoInfo.Branch, nil
+	}
+
+	if provider.Capabilities().DefaultBranch {
+		branch, err := provider.DefaultBranch(repoInfo)
+		if err == nil && branch != "" {
+			return branch, nil
+		}
+		if err != nil {
+			fmt.Println(warning.Render(fmt.Sprintf("Warning: Failed to look up default branch through the API: %v", err)))
+		}
+	}
+
+	// Otherwise ask the remote where its HEAD points, without cloning anything
+	env, err := gitAuth.Env()
+	if err != nil {
+		return "", err
+	}
+	output, err := gitCommandOutput(env, "ls-remote", "--symref", remoteURL, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if ref, ok := strings.CutPrefix(line, "ref: refs/heads/"); ok {
+			branch, _, _ := strings.Cut(ref, "\t")
+			return branch, nil
+		}
+	}
+	return "", errors.New("the remote HEAD does not point to a branch")
+}
+
 func ResetRepo(repoInfo RepoInfo, commitMessage string) error {
+	provider, err := GetProvider(repoInfo.Provider)
+	if err != nil {
+		return err
+	}
+
+	tagFilter, err := NewTagFilter(repoInfo.KeepTags, repoInfo.DeleteTags)
+	if err != nil {
+		return err
+	}
+
+	retagSelector, err := NewRetagSelector(repoInfo.Retag)
+	if err != nil {
+		return err
+	}
+
+	keepHistory := repoInfo.KeepLast > 0 || !repoInfo.SquashBefore.IsZero()
+	if keepHistory && repoInfo.CloneStrategy == CloneShallow {
+		return errors.New("the shallow clone strategy does not fetch the history needed to keep recent commits")
+	}
+	if keepHistory && repoInfo.From != "" {
+		return errors.New("a reset from another ref cannot keep recent history")
+	}
+
+	// Output paths are relative to where goresetit runs, not the clone
+	if repoInfo.ArchiveDir != "" {
+		if repoInfo.ArchiveDir, err = filepath.Abs(repoInfo.ArchiveDir); err != nil {
+			return fmt.Errorf("failed to resolve archive directory: %v", err)
+		}
+	}
+	if repoInfo.Backup != "" {
+		if repoInfo.Backup, err = filepath.Abs(repoInfo.Backup); err != nil {
+			return fmt.Errorf("failed to resolve backup path: %v", err)
+		}
+	}
+	if repoInfo.BackupRemote != "" && isLocalPath(repoInfo.BackupRemote) {
+		if repoInfo.BackupRemote, err = filepath.Abs(repoInfo.BackupRemote); err != nil {
+			return fmt.Errorf("failed to resolve backup remote path: %v", err)
+		}
+	}
+	if err := resolveSSHPaths(&repoInfo); err != nil {
+		return err
+	}
+
+	cloneURL, err := RemoteURL(provider, repoInfo)
+	if err != nil {
+		return err
+	}
+
+	// Authenticate clone and push without ever writing the token to the remote URL
+	gitAuth = NewGitAuth(provider, repoInfo)
+	defer func() { gitAuth = GitAuth{} }()
+
 	// Prepare temporary directory
 	tmpPath := filepath.Join(os.TempDir(), "git-tmp")
 	if err := os.RemoveAll(tmpPath); err != nil {
@@ -71,47 +189,200 @@ func ResetRepo(repoInfo RepoInfo, commitMessage string) error {
 		return fmt.Errorf("failed to change to temporary directory: %v", err)
 	}
 
-	// Clone the repository
-	var cloneURL string
-	switch repoInfo.Provider {
-	case GitHub:
-		cloneURL = fmt.Sprintf("https://github.com/%s/%s.git", repoInfo.FullPath, repoInfo.RepoName)
-	case GitLab:
-		cloneURL = fmt.Sprintf("%s/%s/%s.git", repoInfo.GitLabURL, repoInfo.FullPath, repoInfo.RepoName)
+	// Back up every ref before anything changes on the remote
+	if repoInfo.Backup != "" {
+		if _, err := BackupRepo(cloneURL, tmpPath, repoInfo.Backup); err != nil {
+			return fmt.Errorf("failed to back up repository, aborting before any change: %v", err)
+		}
+	}
+	if repoInfo.BackupRemote != "" {
+		if err := BackupToRemote(cloneURL, tmpPath, repoInfo.BackupRemote, repoInfo.DryRun); err != nil {
+			return fmt.Errorf("failed to back up to %s, aborting before any change: %v", repoInfo.BackupRemote, err)
+		}
+	}
+
+	branch, err := DetectBranch(provider, repoInfo, cloneURL)
+	if err != nil {
+		return fmt.Errorf("failed to detect default branch: %v", err)
 	}
-	fmt.Println(info.Render(fmt.Sprintf("Cloning repository: git clone %s", cloneURL)))
+	fmt.Println(info.Render(fmt.Sprintf("Resetting branch: %s", branch)))
 
-	if err := RunGitCommandWithOutput("clone", cloneURL); err != nil {
-		return fmt.Errorf("failed to clone repository: %v", err)
+	// Clone only what the reset needs, as set by the clone strategy
+	if err := CloneRepo(cloneURL, repoInfo.RepoName, branch, repoInfo.CloneStrategy); err != nil {
+		return err
 	}
 	os.Chdir(repoInfo.RepoName)
 
-	// Perform Git operations
-	gitOperations := []struct {
-		desc string
-		args []string
-	}{
-		{"Creating new orphan branch", []string{"checkout", "--orphan", "temp_branch"}},
-		{"Staging all files", []string{"add", "-A"}},
-		{"Creating initial commit", []string{"commit", "-m", commitMessage}},
-		{"Removing old main branch", []string{"branch", "-D", "main"}},
-		{"Renaming branch to main", []string{"branch", "-m", "main"}},
-	}
-
-	for _, op := range gitOperations {
-		fmt.Println(info.Render(fmt.Sprintf("Executing: git %s", strings.Join(op.args, " "))))
-		if err := RunGitCommandWithOutput(op.args...); err != nil {
-			if !strings.Contains(op.args[0], "branch -D") {
-				return fmt.Errorf("failed to %s: %v", op.desc, err)
+	// A partial clone only has the tags pointing into the branch, the tags to
+	// recreate may be anywhere
+	if len(repoInfo.Retag) > 0 || repoInfo.KeepLatestRelease {
+		if err := FetchTags(repoInfo.CloneStrategy); err != nil {
+			return err
+		}
+	}
+
+	// Record where the remote branch stands, so that the push cannot overwrite
+	// commits pushed while the reset is in progress. Branches of a bare clone
+	// are the ones of the remote.
+	leaseSHA, err := GitCommandOutput("rev-parse", fmt.Sprintf("refs/heads/%s", branch))
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %v", branch, err)
+	}
+	originalTree, err := GitCommandOutput("rev-parse", leaseSHA+"^{tree}")
+	if err != nil {
+		return fmt.Errorf("failed to resolve the tree of %s: %v", branch, err)
+	}
+
+	// The lease may only protect what the backup holds, otherwise a push landing
+	// between the backup and the clone would be lost from both
+	if repoInfo.Backup != "" || repoInfo.BackupRemote != "" {
+		backedUp, err := GitCommandOutput("-C", mirrorPath(tmpPath), "rev-parse", "--verify", fmt.Sprintf("refs/heads/%s", branch))
+		if err != nil {
+			return fmt.Errorf("branch %s is not part of the backup: %v", branch, err)
+		}
+		if backedUp != leaseSHA {
+			return fmt.Errorf("remote branch %s moved from %s to %s after the backup was taken (someone pushed during the reset). "+
+				"Nothing was changed, run goresetit again", branch, backedUp, leaseSHA)
+		}
+	}
+
+	// With --from the new commit snapshots that ref instead of the branch tip,
+	// and the branch must end up with its tree
+	var fromDiff string
+	if repoInfo.From != "" {
+		fromCommit, err := ResolveFrom(repoInfo.From, repoInfo.CloneStrategy)
+		if err != nil {
+			return err
+		}
+		tipTree := originalTree
+		if originalTree, err = GitCommandOutput("rev-parse", fromCommit+"^{tree}"); err != nil {
+			return fmt.Errorf("failed to resolve the tree of %s: %v", repoInfo.From, err)
+		}
+		if fromDiff, err = GitCommandOutput("diff", "--name-status", "--no-renames", tipTree, originalTree); err != nil {
+			return fmt.Errorf("failed to compare %s with the tip of %s: %v", repoInfo.From, branch, err)
+		}
+		fmt.Println(info.Render(fmt.Sprintf("Snapshotting %s (%s) instead of the tip of %s", repoInfo.From, fromCommit, branch)))
+	}
+
+	// Archive the releases first, nothing may be deleted if that fails
+	if repoInfo.ArchiveDir != "" {
+		if err := ArchiveReleases(provider, repoInfo, repoInfo.ArchiveDir); err != nil {
+			return fmt.Errorf("failed to archive releases, aborting before any deletion: %v", err)
+		}
+	}
+
+	// Save the latest release before anything is deleted, to publish it again
+	// afterwards. It is saved outside the workspace and only removed once it
+	// is published again, or if no release was deleted.
+	var keptRelease *SavedRelease
+	releaseDir := ""
+	keepReleaseDir := false
+	if repoInfo.KeepLatestRelease {
+		if releaseDir, err = os.MkdirTemp("", "goresetit-release"); err != nil {
+			return fmt.Errorf("failed to create release directory: %v", err)
+		}
+		defer func() {
+			if !keepReleaseDir {
+				os.RemoveAll(releaseDir)
 			}
+		}()
+
+		keptRelease, err = SaveLatestRelease(provider, repoInfo, releaseDir)
+		if err != nil {
+			return fmt.Errorf("failed to save latest release: %v", err)
 		}
 	}
 
+	// When recent history is kept, only the commits before the cutoff are
+	// squashed and the new root commit gets the tree of the newest of them
+	rootTree := originalTree
+	var keptCommits []string
+	if keepHistory {
+		base, kept, err := SplitHistory(leaseSHA, repoInfo.KeepLast, repoInfo.SquashBefore)
+		if err != nil {
+			return err
+		}
+		if rootTree, err = GitCommandOutput("rev-parse", base+"^{tree}"); err != nil {
+			return fmt.Errorf("failed to resolve the tree of %s: %v", base, err)
+		}
+		keptCommits = kept
+		fmt.Println(info.Render(fmt.Sprintf("Squashing history up to %s, keeping %d recent commits", base, len(kept))))
+	}
+
+	// Build the new root commit straight from the tree of the branch, so the
+	// snapshot is byte-for-byte identical whatever the checkout, attributes or
+	// filters. No working tree is involved.
+	fmt.Println(info.Render(fmt.Sprintf("Executing: git commit-tree %s -m %s", rootTree, commitMessage)))
+	newCommit, err := GitCommandOutput("commit-tree", rootTree, "-m", commitMessage)
+	if err != nil {
+		return fmt.Errorf("failed to create initial commit: %v", err)
+	}
+
+	// Graft the kept commits back on top of it
+	head := newCommit
+	var rewritten map[string]string
+	if len(keptCommits) > 0 {
+		fmt.Println(info.Render(fmt.Sprintf("Rebuilding %d commits on the new root commit", len(keptCommits))))
+		if rewritten, err = GraftCommits(newCommit, keptCommits); err != nil {
+			return err
+		}
+		head = rewritten[keptCommits[len(keptCommits)-1]]
+	}
+
+	fmt.Println(info.Render(fmt.Sprintf("Executing: git update-ref refs/heads/%s %s", branch, head)))
+	if err := RunGitCommandWithOutput("update-ref", fmt.Sprintf("refs/heads/%s", branch), head); err != nil {
+		return fmt.Errorf("failed to point %s at the new commit: %v", branch, err)
+	}
+
+	// Only history may change
+	if err := verifyTree(originalTree, head, "new commit"); err != nil {
+		return err
+	}
+
 	// Handle tags deletion
-	tags, err := GetGitTags()
+	localTags, err := GetGitTags()
 	if err != nil {
 		return fmt.Errorf("failed to list tags: %v", err)
 	}
+	tags, _ := tagFilter.Split(localTags)
+
+	// Tags on kept commits are carried over to the rebuilt commits
+	carried := make(map[string]string)
+	if len(rewritten) > 0 {
+		targets, err := TagTargets()
+		if err != nil {
+			return fmt.Errorf("failed to resolve tags: %v", err)
+		}
+		for _, tag := range tags {
+			if commit, ok := rewritten[targets[tag]]; ok {
+				carried[tag] = commit
+			}
+		}
+	}
+
+	// Snapshot the tags to recreate before they are deleted. The tag of a kept
+	// release is recreated too, unless the tag filters already keep it.
+	retagNames := retagSelector.Select(tags)
+	if keptRelease != nil && !slices.Contains(localTags, keptRelease.TagName) {
+		return fmt.Errorf("tag %s of the latest release was not found in the repository", keptRelease.TagName)
+	}
+	if keptRelease != nil && slices.Contains(tags, keptRelease.TagName) && !slices.Contains(retagNames, keptRelease.TagName) {
+		retagNames = append(retagNames, keptRelease.TagName)
+	}
+	for _, tag := range tags {
+		if _, ok := carried[tag]; ok && !slices.Contains(retagNames, tag) {
+			retagNames = append(retagNames, tag)
+		}
+	}
+
+	var retagged []TagSnapshot
+	for _, name := range retagNames {
+		tag, err := ReadTag(name)
+		if err != nil {
+			return fmt.Errorf("failed to read tag %s: %v", name, err)
+		}
+		retagged = append(retagged, tag)
+	}
 
 	if len(tags) > 0 {
 		fmt.Println(info.Render(fmt.Sprintf("Found %d tags to delete", len(tags))))
@@ -125,40 +396,248 @@ func ResetRepo(repoInfo RepoInfo, commitMessage string) error {
 		fmt.Println(info.Render("No local tags found"))
 	}
 
+	// Recreate the selected tags on the new root commit, and the carried over
+	// tags on their rebuilt commit. They are pushed on their own once the
+	// branch went through, a push only applies its lease to the branch and
+	// would update the tags even when the branch is rejected.
+	pushArgs := []string{"push", fmt.Sprintf("--force-with-lease=refs/heads/%s:%s", branch, leaseSHA), "origin", branch}
+	tagPushArgs := []string{"push", "origin"}
+	retaggedNames := make([]string, 0, len(retagged))
+	for _, tag := range retagged {
+		target, ok := carried[tag.Name]
+		if ok {
+			fmt.Println(info.Render(fmt.Sprintf("Carrying tag %s over to rebuilt commit %s", tag.Name, target)))
+		} else {
+			target = newCommit
+			fmt.Println(info.Render(fmt.Sprintf("Recreating tag %s on the new root commit", tag.Name)))
+		}
+		if err := RecreateTag(tag, target); err != nil {
+			return fmt.Errorf("failed to recreate tag %s: %v", tag.Name, err)
+		}
+		retaggedNames = append(retaggedNames, tag.Name)
+		tagPushArgs = append(tagPushArgs, fmt.Sprintf("+refs/tags/%s", tag.Name))
+	}
+
+	// Tags that did not come down with the clone still live on the remote
+	remoteTags, err := GetRemoteTags()
+	if err != nil {
+		return fmt.Errorf("failed to list remote tags: %v", err)
+	}
+	tags, keptTags := tagFilter.Split(mergeTags(localTags, remoteTags))
+
+	// Recreated tags are force-pushed after the branch instead of being deleted
+	tags = excludeTags(tags, retaggedNames)
+
 	// Handle remote operations
 	if repoInfo.DryRun {
+		if len(keptTags) > 0 {
+			fmt.Println(info.Render(fmt.Sprintf("\nWould keep %d tags:", len(keptTags))))
+			for _, tag := range keptTags {
+				fmt.Println(info.Render(fmt.Sprintf("- Tag %s", tag)))
+			}
+		}
 		if len(tags) > 0 {
-			fmt.Println(info.Render(fmt.Sprintf("\nWould delete %d remote tags: %v", len(tags), tags)))
+			fmt.Println(info.Render(fmt.Sprintf("\nWould delete %d remote tags:", len(tags))))
+			for _, tag := range tags {
+				fmt.Println(info.Render(fmt.Sprintf("- Tag %s", tag)))
+			}
+		}
+		if len(retaggedNames) > 0 {
+			fmt.Println(info.Render(fmt.Sprintf("\nWould recreate %d tags:", len(retaggedNames))))
+			for _, tag := range retaggedNames {
+				if commit, ok := carried[tag]; ok {
+					fmt.Println(info.Render(fmt.Sprintf("- Tag %s on rebuilt commit %s", tag, commit)))
+				} else {
+					fmt.Println(info.Render(fmt.Sprintf("- Tag %s on the new root commit", tag)))
+				}
+			}
+		}
+		if repoInfo.From != "" {
+			printTreeDiff(fromDiff, branch, repoInfo.From)
+		}
+		fmt.Println(info.Render(fmt.Sprintf("Would execute: git %s", strings.Join(pushArgs, " "))))
+		if len(retaggedNames) > 0 {
+			fmt.Println(info.Render(fmt.Sprintf("Would execute: git %s", strings.Join(tagPushArgs, " "))))
+		}
+		if provider.Capabilities().Releases {
+			if err := DeleteReleases(provider, repoInfo); err != nil {
+				return err
+			}
+		}
+		if keptRelease != nil {
+			return RestoreRelease(provider, repoInfo, *keptRelease)
 		}
-		fmt.Println(info.Render("Would execute: git push -f origin main"))
 		return nil
 	}
 
+	// Force push the new branch. Tags and releases are only touched once the
+	// push went through.
+	if err := RunAuthenticatedGitCommand(pushArgs...); err != nil {
+		var cmdErr *CommandError
+		if errors.As(err, &cmdErr) && strings.Contains(cmdErr.Output, "stale info") {
+			return fmt.Errorf("remote branch %s moved since it was cloned at %s (someone pushed during the reset). "+
+				"It was left as is and no tags or releases were deleted, run goresetit again", branch, leaseSHA)
+		}
+		return fmt.Errorf("failed to push changes: %v", err)
+	}
+
+	// Check what actually landed on the remote before deleting anything
+	if err := RunAuthenticatedGitCommand("fetch", "origin", branch); err != nil {
+		return fmt.Errorf("failed to fetch pushed branch: %v", err)
+	}
+	if err := verifyTree(originalTree, "FETCH_HEAD", fmt.Sprintf("remote branch %s", branch)); err != nil {
+		return err
+	}
+
+	if len(retaggedNames) > 0 {
+		if err := RunAuthenticatedGitCommand(tagPushArgs...); err != nil {
+			return fmt.Errorf("branch %s was reset but the recreated tags could not be pushed, no tags or releases were deleted: %v", branch, err)
+		}
+	}
+
+	// Delete releases before their tags, Gitea and Forgejo refuse to delete a
+	// tag that still has a release
+	keepReleaseDir = keptRelease != nil
+	if provider.Capabilities().Releases {
+		if err := DeleteReleases(provider, repoInfo); err != nil {
+			if keptRelease != nil {
+				return fmt.Errorf("%v (release %s was saved to %s)", err, releaseLabel(keptRelease.Release), releaseDir)
+			}
+			return err
+		}
+	}
+
 	// Delete remote tags
+	if len(keptTags) > 0 {
+		fmt.Println(info.Render(fmt.Sprintf("Keeping %d tags matching the tag filters", len(keptTags))))
+	}
 	if len(tags) > 0 {
+		DeleteRemoteTags(provider, repoInfo, tags)
+	}
+
+	// The tag of the kept release was pushed with the recreated tags
+	if keptRelease != nil {
+		if err := RestoreRelease(provider, repoInfo, *keptRelease); err != nil {
+			return fmt.Errorf("failed to recreate release %s, its notes and assets were saved to %s: %v", releaseLabel(keptRelease.Release), releaseDir, err)
+		}
+		keepReleaseDir = false
+	}
+	return nil
+}
+
+// printTreeDiff shows the files that change when the tip of branch is
+// replaced with the content of from, as listed by git diff --name-status
+func printTreeDiff(diff, branch, from string) {
+	if diff == "" {
+		fmt.Println(info.Render(fmt.Sprintf("\n%s has the same content as the tip of %s", from, branch)))
+		return
+	}
+
+	lines := strings.Split(diff, "\n")
+	fmt.Println(info.Render(fmt.Sprintf("\nWould change %d files from the tip of %s to the content of %s:", len(lines), branch, from)))
+	for _, line := range lines {
+		status, file, _ := strings.Cut(line, "\t")
+		switch status {
+		case "D":
+			fmt.Println(warning.Render(fmt.Sprintf("- Removed: %s", file)))
+		case "A":
+			fmt.Println(info.Render(fmt.Sprintf("- Added: %s", file)))
+		default:
+			fmt.Println(info.Render(fmt.Sprintf("- Modified: %s", file)))
+		}
+	}
+}
+
+// verifyTree checks that the tree of rev is the original tree, and lists the
+// files that differ when it is not
+func verifyTree(originalTree, rev, description string) error {
+	tree, err := GitCommandOutput("rev-parse", rev+"^{tree}")
+	if err != nil {
+		return fmt.Errorf("failed to resolve the tree of %s: %v", description, err)
+	}
+	if tree == originalTree {
+		return nil
+	}
+
+	diff, err := GitCommandOutput("diff", "--name-status", "--no-renames", originalTree, tree)
+	if err != nil {
+		return fmt.Errorf("tree of %s (%s) does not match the original tree (%s)", description, tree, originalTree)
+	}
+	return fmt.Errorf("tree of %s (%s) does not match the original tree (%s), files differ:\n%s", description, tree, originalTree, diff)
+}
+
+// DeleteRemoteTags deletes tags through the provider API when supported, or
+// with batched git pushes otherwise, reporting the outcome of every tag
+func DeleteRemoteTags(provider Provider, repoInfo RepoInfo, tags []string) {
+	results := make(map[string]error, len(tags))
+	if deleter, ok := provider.(TagDeleter); ok {
 		for _, tag := range tags {
-			if err := RunGitCommandWithOutput("push", "origin", "--delete", fmt.Sprintf("refs/tags/%s", tag)); err != nil {
-				fmt.Println(warning.Render(fmt.Sprintf("Warning: Failed to delete remote tag %s: %v", tag, err)))
-			} else {
-				fmt.Println(success.Render(fmt.Sprintf("Deleted remote tag: %s", tag)))
+			results[tag] = deleter.DeleteTag(repoInfo, tag)
+		}
+	} else {
+		for start := 0; start < len(tags); start += tagPushBatchSize {
+			end := min(start+tagPushBatchSize, len(tags))
+			for tag, err := range pushDeleteTags(tags[start:end]) {
+				results[tag] = err
 			}
 		}
 	}
 
-	// Force push the new main branch
-	if err := RunGitCommandWithOutput("push", "-f", "origin", "main"); err != nil {
-		return fmt.Errorf("failed to push changes: %v", err)
+	for _, tag := range tags {
+		if err := results[tag]; err != nil {
+			fmt.Println(warning.Render(fmt.Sprintf("Warning: Failed to delete remote tag %s: %v", tag, err)))
+		} else {
+			fmt.Println(success.Render(fmt.Sprintf("Deleted remote tag: %s", tag)))
+		}
+	}
+}
+
+// pushDeleteTags deletes tags with a single git push and returns the outcome
+// of each one, as reported by the porcelain output
+func pushDeleteTags(tags []string) map[string]error {
+	args := []string{"push", "--porcelain", "origin"}
+	for _, tag := range tags {
+		args = append(args, fmt.Sprintf(":refs/tags/%s", tag))
+	}
+
+	env, err := gitAuth.Env()
+	var output string
+	if err == nil {
+		output, err = gitCommandOutput(env, args...)
+	}
+
+	// Porcelain status lines read "<flag>\t<from>:<to>\t<summary>", where a
+	// "!" flag marks a rejected ref
+	results := make(map[string]error, len(tags))
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		tag, ok := strings.CutPrefix(fields[1], ":refs/tags/")
+		if !ok {
+			continue
+		}
+		if fields[0] == "!" {
+			results[tag] = errors.New(fields[2])
+		} else {
+			results[tag] = nil
+		}
 	}
 
-	// Delete releases
-	switch repoInfo.Provider {
-	case GitHub:
-		return DeleteGitHubReleases(repoInfo)
-	case GitLab:
-		return DeleteGitLabReleases(repoInfo)
-	default:
-		return fmt.Errorf("unsupported git provider")
+	// Tags missing from the output were never attempted, typically because the
+	// push failed as a whole
+	for _, tag := range tags {
+		if _, ok := results[tag]; ok {
+			continue
+		}
+		if err != nil {
+			results[tag] = err
+		} else {
+			results[tag] = errors.New("no status reported by git push")
+		}
 	}
+	return results
 }
 
 func GetGitTags() ([]string, error) {
@@ -179,89 +658,60 @@ func GetGitTags() ([]string, error) {
 	return tags, nil
 }
 
-func DeleteGitHubReleases(repoInfo RepoInfo) error {
-	client := newGitHubClient(repoInfo.Token)
-	ctx := context.Background()
-
-	releases, _, err := client.Repositories.ListReleases(ctx, repoInfo.FullPath, repoInfo.RepoName, nil)
+// GetRemoteTags lists the tags on the remote with git ls-remote, including
+// those that were not fetched or point at unreachable objects
+func GetRemoteTags() ([]string, error) {
+	env, err := gitAuth.Env()
 	if err != nil {
-		return fmt.Errorf("failed to list releases: %v", err)
+		return nil, err
 	}
-
-	if len(releases) == 0 {
-		fmt.Println(info.Render("No releases found to delete"))
-		return nil
+	output, err := gitCommandOutput(env, "ls-remote", "--tags", "origin")
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Println(info.Render(fmt.Sprintf("Found %d releases", len(releases))))
-
-	if repoInfo.DryRun {
-		fmt.Println(info.Render("\nThe following releases would be deleted:"))
-		for _, release := range releases {
-			fmt.Println(info.Render(fmt.Sprintf("- Release %d: %s (tag: %s)",
-				*release.ID,
-				*release.Name,
-				*release.TagName)))
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
 		}
-	} else {
-		for _, release := range releases {
-			_, err := client.Repositories.DeleteRelease(ctx, repoInfo.FullPath, repoInfo.RepoName, *release.ID)
-			if err != nil {
-				fmt.Println(warning.Render(fmt.Sprintf("Warning: Failed to delete release %d: %v", *release.ID, err)))
-			} else {
-				fmt.Println(success.Render(fmt.Sprintf("Deleted release %d: %s", *release.ID, *release.Name)))
-			}
+		tag, ok := strings.CutPrefix(fields[1], "refs/tags/")
+		if !ok {
+			continue
+		}
+		// Annotated tags are listed a second time, peeled to their target
+		tag = strings.TrimSuffix(tag, "^{}")
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
 		}
 	}
-
-	return nil
+	return tags, nil
 }
 
-func DeleteGitLabReleases(repoInfo RepoInfo) error {
-	client, err := newGitLabClient(repoInfo.Token, repoInfo.GitLabURL)
-	if err != nil {
-		return fmt.Errorf("failed to create GitLab client: %v", err)
-	}
-
-	fullPath := repoInfo.FullPath + "/" + repoInfo.RepoName
-	releases, resp, err := client.Releases.ListReleases(fullPath, nil)
-	if err != nil {
-		if resp != nil {
-			return fmt.Errorf("failed to list releases (status %d): %v", resp.StatusCode, err)
+// excludeTags returns the tags that are not in exclude
+func excludeTags(tags, exclude []string) []string {
+	remaining := []string{}
+	for _, tag := range tags {
+		if !slices.Contains(exclude, tag) {
+			remaining = append(remaining, tag)
 		}
-		return fmt.Errorf("failed to list releases: %v", err)
 	}
+	return remaining
+}
 
-	if len(releases) == 0 {
-		fmt.Println(info.Render("No releases found to delete"))
-		return nil
-	}
-
-	fmt.Println(info.Render("Found %d releases", len(releases)))
-
-	if repoInfo.DryRun {
-		fmt.Println(info.Render("\nThe following releases would be deleted:"))
-		for _, release := range releases {
-			fmt.Println(info.Render(fmt.Sprintf("- Release: %s (tag: %s)",
-				release.Name,
-				release.TagName)))
-		}
-	} else {
-		for _, release := range releases {
-			_, resp, err := client.Releases.DeleteRelease(fullPath, release.TagName)
-			if err != nil {
-				if resp != nil {
-					fmt.Println(warning.Render(fmt.Sprintf("Warning: Failed to delete release %s (status %d): %v",
-						release.TagName, resp.StatusCode, err)))
-				} else {
-					fmt.Println(warning.Render(fmt.Sprintf("Warning: Failed to delete release %s: %v",
-						release.TagName, err)))
-				}
-			} else {
-				fmt.Println(success.Render(fmt.Sprintf("Deleted release: %s", release.Name)))
-			}
+// mergeTags returns the sorted union of two tag lists
+func mergeTags(local, remote []string) []string {
+	seen := make(map[string]bool, len(local)+len(remote))
+	tags := []string{}
+	for _, tag := range append(append([]string{}, local...), remote...) {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
 		}
 	}
-
-	return nil
+	sort.Strings(tags)
+	return tags
 }
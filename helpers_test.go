@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureOutput captures stdout for testing
+func captureOutput(f func()) string {
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	f()
+
+	w.Close()
+	os.Stdout = old
+
+	var buf strings.Builder
+	io.Copy(&buf, r)
+	return buf.String()
+}
+
+// setupTestEnv creates a temporary directory and changes to it
+func setupTestEnv(t *testing.T) (cleanup func()) {
+	t.Helper()
+
+	// Save current directory
+	currentDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create and change to temporary directory
+	tmpDir, err := os.MkdirTemp("", "goresetit-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chdir(tmpDir); err != nil {
+		os.RemoveAll(tmpDir)
+		t.Fatal(err)
+	}
+
+	// Return cleanup function
+	return func() {
+		os.Chdir(currentDir)
+		os.RemoveAll(tmpDir)
+	}
+}
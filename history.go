@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CommitSnapshot is a commit read back to be rebuilt on the new root commit
+type CommitSnapshot struct {
+	SHA            string
+	AuthorName     string
+	AuthorEmail    string
+	AuthorDate     string
+	CommitterName  string
+	CommitterEmail string
+	CommitterDate  string
+	Encoding       string
+	Tree           string
+	Message        string
+}
+
+// ParseCutoffDate parses a --squash-before date, either a day such as
+// 2024-01-31 (midnight UTC) or an RFC 3339 timestamp
+func ParseCutoffDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s', use YYYY-MM-DD or an RFC 3339 timestamp", value)
+	}
+	return date, nil
+}
+
+// formatCutoff renders a --squash-before date the way it was most likely given
+func formatCutoff(before time.Time) string {
+	if before.Equal(before.Truncate(24 * time.Hour)) {
+		return before.Format(time.DateOnly)
+	}
+	return before.Format(time.RFC3339)
+}
+
+// SplitHistory walks the first-parent history of tip and returns the newest
+// commit to squash into the new root commit, along with the commits to keep
+// on top of it, oldest first. keepLast keeps that many recent commits, and
+// before keeps the commits committed from that date on.
+func SplitHistory(tip string, keepLast int, before time.Time) (string, []string, error) {
+	args := []string{"log", "--first-parent", "--format=%H %ct"}
+	if keepLast > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", keepLast+1))
+	}
+	output, err := GitCommandOutput(append(args, tip)...)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read history: %v", err)
+	}
+
+	var kept []string
+	base := ""
+	for _, line := range strings.Split(output, "\n") {
+		sha, timestamp, _ := strings.Cut(line, " ")
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to parse the date of commit %s: %v", sha, err)
+		}
+		if (keepLast > 0 && len(kept) == keepLast) || (keepLast == 0 && seconds < before.Unix()) {
+			base = sha
+			break
+		}
+		kept = append(kept, sha)
+	}
+	if base == "" {
+		return "", nil, fmt.Errorf("all %d commits are recent enough to be kept, there is nothing to squash", len(kept))
+	}
+
+	// Oldest first, in the order they are rebuilt
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	return base, kept, nil
+}
+
+// GraftCommits rebuilds commits, oldest first, on top of root with their own
+// tree, authors, dates and messages, and returns the new SHA of each one.
+// Every commit gets the previous one as its only parent, so merges become
+// regular commits of the first-parent line. Signatures cannot survive.
+func GraftCommits(root string, commits []string) (map[string]string, error) {
+	rewritten := make(map[string]string, len(commits))
+	parent := root
+	for _, sha := range commits {
+		commit, err := ReadCommit(sha)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read commit %s: %v", sha, err)
+		}
+		if parent, err = rebuildCommit(commit, parent); err != nil {
+			return nil, fmt.Errorf("failed to rebuild commit %s: %v", sha, err)
+		}
+		rewritten[sha] = parent
+	}
+	return rewritten, nil
+}
+
+// ReadCommit snapshots a commit, keeping its message byte for byte
+func ReadCommit(sha string) (CommitSnapshot, error) {
+	commit := CommitSnapshot{SHA: sha}
+
+	raw, err := exec.Command("git", "cat-file", "commit", sha).Output()
+	if err != nil {
+		return commit, &CommandError{Command: "git cat-file commit " + sha, Err: err}
+	}
+
+	header, message, _ := strings.Cut(string(raw), "\n\n")
+	commit.Message = message
+	for _, line := range strings.Split(header, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "tree":
+			commit.Tree = value
+		case "author":
+			commit.AuthorName, commit.AuthorEmail, commit.AuthorDate = parseIdent(value)
+		case "committer":
+			commit.CommitterName, commit.CommitterEmail, commit.CommitterDate = parseIdent(value)
+		case "encoding":
+			commit.Encoding = value
+		}
+	}
+	return commit, nil
+}
+
+// parseIdent splits "Name <email> 1700000000 +0000" into its parts
+func parseIdent(ident string) (string, string, string) {
+	name, rest, _ := strings.Cut(ident, " <")
+	email, date, _ := strings.Cut(rest, "> ")
+	return name, email, date
+}
+
+// rebuildCommit writes commit again with parent as its only parent
+func rebuildCommit(commit CommitSnapshot, parent string) (string, error) {
+	var args []string
+	if commit.Encoding != "" {
+		args = append(args, "-c", "i18n.commitEncoding="+commit.Encoding)
+	}
+	args = append(args, "commit-tree", commit.Tree, "-p", parent, "-F", "-")
+
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME="+commit.AuthorName,
+		"GIT_AUTHOR_EMAIL="+commit.AuthorEmail,
+		"GIT_AUTHOR_DATE="+commit.AuthorDate,
+		"GIT_COMMITTER_NAME="+commit.CommitterName,
+		"GIT_COMMITTER_EMAIL="+commit.CommitterEmail,
+		"GIT_COMMITTER_DATE="+commit.CommitterDate,
+	)
+	cmd.Stdin = strings.NewReader(commit.Message)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", &CommandError{
+			Command: "git " + strings.Join(args, " "),
+			Output:  stderr.String(),
+			Err:     err,
+		}
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// TagTargets returns the commit each local tag points to, annotated tags
+// being peeled
+func TagTargets() (map[string]string, error) {
+	output, err := GitCommandOutput("for-each-ref", "--format=%(refname:strip=2) %(objectname) %(*objectname)", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		targets[fields[0]] = fields[len(fields)-1]
+	}
+	return targets, nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCutoffDate(t *testing.T) {
+	testCases := []struct {
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{"2024-01-31", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), false},
+		{"2024-01-31T12:30:00+02:00", time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC), false},
+		{"31/01/2024", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			date, err := ParseCutoffDate(tc.value)
+
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !date.Equal(tc.expected) {
+				t.Errorf("Expected %s, got %s", tc.expected, date)
+			}
+		})
+	}
+}
+
+func TestResetRepoKeepLast(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	originalTree := git(t, bare, "rev-parse", "main^{tree}")
+	originalBase := git(t, bare, "rev-parse", "main~1^{tree}")
+	originalLog := git(t, bare, "log", "-1", "--format=%an %ae %ad %cn %ce %cd %B", "--date=raw", "main")
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+		KeepLast: 1,
+	}
+
+	if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if count := git(t, bare, "rev-list", "--count", "main"); count != "2" {
+		t.Errorf("Expected the new root commit and one kept commit, got %s commits", count)
+	}
+	if tree := git(t, bare, "rev-parse", "main^{tree}"); tree != originalTree {
+		t.Errorf("Expected tree %s to be preserved, got %s", originalTree, tree)
+	}
+	if tree := git(t, bare, "rev-parse", "main~1^{tree}"); tree != originalBase {
+		t.Errorf("Expected the new root commit to have the tree of the last squashed commit %s, got %s", originalBase, tree)
+	}
+	if message := git(t, bare, "log", "-1", "--format=%s", "main~1"); message != "fresh start" {
+		t.Errorf("Expected the new root commit message 'fresh start', got '%s'", message)
+	}
+	if log := git(t, bare, "log", "-1", "--format=%an %ae %ad %cn %ce %cd %B", "--date=raw", "main"); log != originalLog {
+		t.Errorf("Expected the kept commit to be rebuilt as %q, got %q", originalLog, log)
+	}
+	if tags := git(t, bare, "tag"); tags != "v1.2" {
+		t.Errorf("Expected only the tag of the kept commit to remain, got %s", tags)
+	}
+	if target, head := git(t, bare, "rev-parse", "v1.2^{commit}"), git(t, bare, "rev-parse", "main"); target != head {
+		t.Errorf("Expected v1.2 to be carried over to the rebuilt commit %s, got %s", head, target)
+	}
+}
+
+func TestResetRepoKeepLastNothingToSquash(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	originalHead := git(t, bare, "rev-parse", "main")
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+		KeepLast: 3,
+	}
+
+	err := ResetRepo(repoInfo, "fresh start")
+	if err == nil || !strings.Contains(err.Error(), "nothing to squash") {
+		t.Fatalf("Expected nothing to squash, got %v", err)
+	}
+	if head := git(t, bare, "rev-parse", "main"); head != originalHead {
+		t.Errorf("Expected main to be left at %s, got %s", originalHead, head)
+	}
+}
+
+func TestResetRepoSquashBefore(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	// The history of setupBareRepo dates back to 2020, two commits are recent
+	t.Setenv("GIT_AUTHOR_DATE", "2020-01-01T00:00:00Z")
+	t.Setenv("GIT_COMMITTER_DATE", "2020-01-01T00:00:00Z")
+	bare := setupBareRepo(t, "main")
+	os.Unsetenv("GIT_AUTHOR_DATE")
+	os.Unsetenv("GIT_COMMITTER_DATE")
+
+	work := filepath.Join(t.TempDir(), "work")
+	git(t, filepath.Dir(work), "clone", bare, work)
+	for _, content := range []string{"four", "five"} {
+		if err := os.WriteFile(filepath.Join(work, "file.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git(t, work, "commit", "-am", "commit "+content)
+	}
+	git(t, work, "tag", "-a", "v2.0.0", "-m", "Version 2.0.0", "HEAD~1")
+	git(t, work, "push", "origin", "main", "v2.0.0")
+	originalTree := git(t, bare, "rev-parse", "main^{tree}")
+
+	cutoff, err := ParseCutoffDate("2024-01-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoInfo := RepoInfo{
+		Provider:     GitRemote,
+		FullPath:     bare,
+		RepoName:     "repo",
+		SquashBefore: cutoff,
+	}
+
+	if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if log := git(t, bare, "log", "--format=%s", "main"); log != "commit five\ncommit four\nfresh start" {
+		t.Errorf("Expected the recent commits on top of the new root commit, got %s", log)
+	}
+	if tree := git(t, bare, "rev-parse", "main^{tree}"); tree != originalTree {
+		t.Errorf("Expected tree %s to be preserved, got %s", originalTree, tree)
+	}
+	if tags := git(t, bare, "tag"); tags != "v2.0.0" {
+		t.Errorf("Expected only the tag of the kept commits to remain, got %s", tags)
+	}
+	if target, kept := git(t, bare, "rev-parse", "v2.0.0^{commit}"), git(t, bare, "rev-parse", "main~1"); target != kept {
+		t.Errorf("Expected v2.0.0 to be carried over to the rebuilt commit %s, got %s", kept, target)
+	}
+	if message := git(t, bare, "for-each-ref", "--format=%(contents)", "refs/tags/v2.0.0"); message != "Version 2.0.0" {
+		t.Errorf("Expected the annotated tag message to be preserved, got %q", message)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 )
 
@@ -15,7 +16,7 @@ const (
 	defaultCommitMsg = "Initial commit"
 )
 
-func parseFlags() CommandLineFlags {
+func parseFlags(args []string) CommandLineFlags {
 	flags := CommandLineFlags{}
 
 	fs := flag.NewFlagSet("goresetit", flag.ExitOnError)
@@ -26,20 +27,70 @@ func parseFlags() CommandLineFlags {
 
 	// Repository path
 	fs.StringVar(&flags.RepoPath, "repo", "", "")
-	fs.StringVar(&flags.RepoPath, "r", "", "Repository path (e.g., owner/repo or group/subgroup/repo)")
+	fs.StringVar(&flags.RepoPath, "r", "", "Repository path (e.g., owner/repo or group/subgroup/repo, or a remote URL with -p git)")
 
 	// Token
 	fs.StringVar(&flags.Token, "token", "", "")
 	fs.StringVar(&flags.Token, "t", "", "Personal access token")
+	fs.StringVar(&flags.TokenFile, "token-file", "", "File containing the personal access token")
 
 	// Provider
 	fs.StringVar(&flags.Provider, "provider", "github", "")
-	fs.StringVar(&flags.Provider, "p", "github", "Git provider (github or gitlab)")
+	fs.StringVar(&flags.Provider, "p", "github", fmt.Sprintf("Git provider (%s)", strings.Join(ProviderNames(), ", ")))
+
+	// GitHub URL
+	fs.StringVar(&flags.GitHubURL, "github-url", gitHubCloudURL, "GitHub Enterprise Server URL (for private instances)")
 
 	// GitLab URL
 	fs.StringVar(&flags.GitLabURL, "gitlab-url", "https://gitlab.com", "")
 	fs.StringVar(&flags.GitLabURL, "g", "https://gitlab.com", "GitLab instance URL (for private instances)")
 
+	// Gitea URL
+	fs.StringVar(&flags.GiteaURL, "gitea-url", "https://gitea.com", "Gitea or Forgejo instance URL (for private instances)")
+
+	// Bitbucket URL
+	fs.StringVar(&flags.BitbucketURL, "bitbucket-url", "https://bitbucket.org", "Bitbucket Cloud or Bitbucket Data Center URL")
+
+	// Azure DevOps URL
+	fs.StringVar(&flags.AzureURL, "azure-url", "https://dev.azure.com", "Azure DevOps URL (for Azure DevOps Server)")
+
+	// Transport
+	fs.StringVar(&flags.Transport, "transport", "https", "Transport used for clone and push (https or ssh)")
+	fs.StringVar(&flags.SSHKey, "ssh-key", "", "SSH private key used with --transport ssh")
+	fs.StringVar(&flags.KnownHosts, "known-hosts", "", "Known hosts file used with --transport ssh (enables strict host key checking)")
+
+	// Branch
+	fs.StringVar(&flags.Branch, "branch", "", "")
+	fs.StringVar(&flags.Branch, "b", "", "Branch to reset (default: the repository default branch)")
+
+	// Clone strategy
+	fs.StringVar(&flags.CloneStrategy, "clone-strategy", string(CloneBlobless), fmt.Sprintf("How much of the repository to clone (%s)", strings.Join(CloneStrategies(), ", ")))
+
+	// Snapshot another ref
+	fs.StringVar(&flags.From, "from", "", "Ref or SHA whose content the new commit snapshots (default: the branch tip)")
+
+	// Recent history
+	fs.IntVar(&flags.KeepLast, "keep-last", 0, "Keep the last N commits, rebuilt on top of the new root commit")
+	fs.StringVar(&flags.SquashBefore, "squash-before", "", "Only squash commits older than this date (YYYY-MM-DD or RFC 3339)")
+
+	// Tag filters
+	fs.StringVar(&flags.KeepTags, "keep-tags", "", "Comma-separated tag globs or /regexes/ to keep")
+	fs.StringVar(&flags.DeleteTags, "delete-tags", "", "Comma-separated tag globs or /regexes/ to delete (default: all tags)")
+
+	// Retag
+	fs.StringVar(&flags.Retag, "retag", "", "Tags to recreate on the new root commit ('latest' for the highest semver tag, tag globs or /regexes/)")
+
+	// Keep latest release
+	fs.BoolVar(&flags.KeepLatestRelease, "keep-latest-release", false, "Recreate the latest release on the new root commit (GitHub and GitLab)")
+
+	// Archive directory
+	fs.StringVar(&flags.ArchiveDir, "archive-dir", "", "Directory to archive releases and their assets to before deleting them (GitHub and GitLab)")
+
+	// Backup and restore
+	fs.StringVar(&flags.Backup, "backup", "", "Write a git bundle of all branches, tags and notes to this file before the reset")
+	fs.StringVar(&flags.BackupRemote, "backup-remote", "", "Push all branches, tags and notes to this remote and verify them before the reset")
+	fs.StringVar(&flags.Bundle, "bundle", "", "Bundle to push back to the remote (restore command)")
+
 	// Dry run
 	fs.BoolVar(&flags.DryRun, "dry-run", false, "")
 	fs.BoolVar(&flags.DryRun, "d", false, "Perform a dry run without making actual changes")
@@ -55,13 +106,46 @@ func parseFlags() CommandLineFlags {
 	// Custom usage message
 	fs.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage of GoresetIT:\n")
-		fmt.Fprintf(os.Stderr, "  goresetit -r owner/repo -t <token> [options]\n\n")
+		fmt.Fprintf(os.Stderr, "  goresetit -r owner/repo [-t <token> | --token-file <file>] [options]\n")
+		fmt.Fprintf(os.Stderr, "  goresetit restore --bundle <file> -r owner/repo [-t <token> | --token-file <file>] [options]\n\n")
+		fmt.Fprintf(os.Stderr, "The token is read, in order, from --token, --token-file, the <PROVIDER>_TOKEN\n")
+		fmt.Fprintf(os.Stderr, "(e.g. GITHUB_TOKEN) or GORESETIT_TOKEN environment variables, or git credential fill.\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		fmt.Fprintf(os.Stderr, "  -v, --version            Show version information\n")
-		fmt.Fprintf(os.Stderr, "  -r, --repo string        Repository path (e.g., owner/repo or group/subgroup/repo)\n")
-		fmt.Fprintf(os.Stderr, "  -t, --token string       Personal access token\n")
-		fmt.Fprintf(os.Stderr, "  -p, --provider string    Git provider (github or gitlab) (default: github)\n")
+		fmt.Fprintf(os.Stderr, "  -r, --repo string        Repository path (e.g., owner/repo or group/subgroup/repo, or a remote URL with -p git)\n")
+		fmt.Fprintf(os.Stderr, "  -t, --token string       Personal access token (prefer --token-file or environment variables)\n")
+		fmt.Fprintf(os.Stderr, "      --token-file string  File containing the personal access token\n")
+		fmt.Fprintf(os.Stderr, "  -p, --provider string    Git provider (%s) (default: github)\n", strings.Join(ProviderNames(), ", "))
+		fmt.Fprintf(os.Stderr, "      --github-url string  GitHub Enterprise Server URL (for private instances) (default: https://github.com)\n")
 		fmt.Fprintf(os.Stderr, "  -g, --gitlab-url string  GitLab instance URL (for private instances) (default: https://gitlab.com)\n")
+		fmt.Fprintf(os.Stderr, "      --gitea-url string   Gitea or Forgejo instance URL (default: https://gitea.com)\n")
+		fmt.Fprintf(os.Stderr, "      --bitbucket-url string  Bitbucket Cloud or Data Center URL (default: https://bitbucket.org)\n")
+		fmt.Fprintf(os.Stderr, "      --azure-url string   Azure DevOps URL (for Azure DevOps Server) (default: https://dev.azure.com)\n")
+		fmt.Fprintf(os.Stderr, "      --transport string   Transport used for clone and push (https or ssh) (default: https)\n")
+		fmt.Fprintf(os.Stderr, "      --ssh-key string     SSH private key used with --transport ssh\n")
+		fmt.Fprintf(os.Stderr, "      --known-hosts string Known hosts file used with --transport ssh (enables strict host key checking)\n")
+		fmt.Fprintf(os.Stderr, "  -b, --branch string      Branch to reset (default: the repository default branch)\n")
+		fmt.Fprintf(os.Stderr, "      --clone-strategy string How much of the repository to clone: blobless (trees only), shallow\n")
+		fmt.Fprintf(os.Stderr, "                           (tip commit only) or full (default: blobless)\n")
+		fmt.Fprintf(os.Stderr, "      --from string        Ref or SHA (e.g. a release tag) whose content the new commit snapshots\n")
+		fmt.Fprintf(os.Stderr, "                           instead of the branch tip, dry runs list the files that change\n")
+		fmt.Fprintf(os.Stderr, "      --keep-last int      Keep the last N commits of the branch, rebuilt on top of the new root\n")
+		fmt.Fprintf(os.Stderr, "                           commit with their authors, dates and messages\n")
+		fmt.Fprintf(os.Stderr, "      --squash-before string Only squash commits older than this date (YYYY-MM-DD or RFC 3339),\n")
+		fmt.Fprintf(os.Stderr, "                           newer commits are kept like with --keep-last\n")
+		fmt.Fprintf(os.Stderr, "      --keep-tags string   Comma-separated tag globs or /regexes/ to keep (e.g. 'v1.*')\n")
+		fmt.Fprintf(os.Stderr, "      --delete-tags string Comma-separated tag globs or /regexes/ to delete (default: all tags)\n")
+		fmt.Fprintf(os.Stderr, "      --retag string       Tags to recreate on the new root commit ('latest' for the highest semver tag,\n")
+		fmt.Fprintf(os.Stderr, "                           tag globs or /regexes/), force-pushed after the branch\n")
+		fmt.Fprintf(os.Stderr, "      --keep-latest-release Recreate the latest release, with its notes and assets, on the new root\n")
+		fmt.Fprintf(os.Stderr, "                           commit (GitHub and GitLab)\n")
+		fmt.Fprintf(os.Stderr, "      --archive-dir string Archive releases and their assets to this directory before deleting them\n")
+		fmt.Fprintf(os.Stderr, "                           (GitHub and GitLab)\n")
+		fmt.Fprintf(os.Stderr, "      --backup string      Write a git bundle of all branches, tags and notes, and a manifest of\n")
+		fmt.Fprintf(os.Stderr, "                           their SHAs (<file>.json), before the reset\n")
+		fmt.Fprintf(os.Stderr, "      --backup-remote string Push all branches, tags and notes to this remote and verify every ref\n")
+		fmt.Fprintf(os.Stderr, "                           before the reset (authenticates with your own git configuration)\n")
+		fmt.Fprintf(os.Stderr, "      --bundle string      Bundle written by --backup to force-push back (restore command)\n")
 		fmt.Fprintf(os.Stderr, "  -d, --dry-run           Perform a dry run without making actual changes\n")
 		fmt.Fprintf(os.Stderr, "  -n, --no-interactive    Run without interactive prompts (uses default commit message if -m not provided)\n")
 		fmt.Fprintf(os.Stderr, "  -m, --message string     Specify commit message (skips message prompt if provided)\n\n")
@@ -71,10 +155,25 @@ func parseFlags() CommandLineFlags {
 		fmt.Fprintf(os.Stderr, "  # Non-interactive mode with custom commit message:\n")
 		fmt.Fprintf(os.Stderr, "  goresetit -r owner/repo -t <token> -n -m \"feat: fresh start\"\n\n")
 		fmt.Fprintf(os.Stderr, "  # Dry run with default commit message:\n")
-		fmt.Fprintf(os.Stderr, "  goresetit -r owner/repo -t <token> -d -n\n")
+		fmt.Fprintf(os.Stderr, "  goresetit -r owner/repo -t <token> -d -n\n\n")
+		fmt.Fprintf(os.Stderr, "  # Clone and push over SSH, using the token only for releases:\n")
+		fmt.Fprintf(os.Stderr, "  goresetit -r owner/repo -t <token> --transport ssh --ssh-key ~/.ssh/id_ed25519\n\n")
+		fmt.Fprintf(os.Stderr, "  # Keep v1 tags and only drop pre-release tags:\n")
+		fmt.Fprintf(os.Stderr, "  goresetit -r owner/repo -t <token> --keep-tags 'v1.*' --delete-tags '/-(alpha|beta|rc)/' -d\n\n")
+		fmt.Fprintf(os.Stderr, "  # Keep the latest release tag, pointing at the new root commit:\n")
+		fmt.Fprintf(os.Stderr, "  goresetit -r owner/repo -t <token> --retag latest\n\n")
+		fmt.Fprintf(os.Stderr, "  # Squash everything but the last 20 commits, keeping their tags:\n")
+		fmt.Fprintf(os.Stderr, "  goresetit -r owner/repo -t <token> --keep-last 20\n\n")
+		fmt.Fprintf(os.Stderr, "  # Preview resetting the branch to the content of a release:\n")
+		fmt.Fprintf(os.Stderr, "  goresetit -r owner/repo -t <token> --from v1.4.0 -d\n\n")
+		fmt.Fprintf(os.Stderr, "  # Back up the repository, then put it back as it was:\n")
+		fmt.Fprintf(os.Stderr, "  goresetit -r owner/repo -t <token> --backup repo.bundle\n")
+		fmt.Fprintf(os.Stderr, "  goresetit restore -r owner/repo -t <token> --bundle repo.bundle\n\n")
+		fmt.Fprintf(os.Stderr, "  # Any git remote, without a forge API (no releases, no token needed):\n")
+		fmt.Fprintf(os.Stderr, "  goresetit -p git -r ssh://git@example.com/srv/repo.git -n\n")
 	}
 
-	fs.Parse(os.Args[1:])
+	fs.Parse(args)
 
 	// Show version and exit if requested
 	if *showVersion {
@@ -86,42 +185,136 @@ func parseFlags() CommandLineFlags {
 }
 
 func main() {
+	// goresetit is its own GIT_ASKPASS helper, see GitAuth
+	if HandleAskPass() {
+		return
+	}
+
 	ShowLogo()
 
-	flags := parseFlags()
+	// "goresetit restore" pushes a backup bundle back instead of resetting
+	args := os.Args[1:]
+	restore := len(args) > 0 && args[0] == "restore"
+	if restore {
+		args = args[1:]
+	}
 
-	if flags.RepoPath == "" || flags.Token == "" {
+	flags := parseFlags(args)
+
+	provider, err := GetProvider(GitProvider(flags.Provider))
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: Invalid provider. Use one of: %s.", strings.Join(ProviderNames(), ", "))))
+		os.Exit(1)
+	}
+
+	if flags.RepoPath == "" {
 		fmt.Println(errorStyle.Render("Error: Missing required arguments."))
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	parts := strings.Split(flags.RepoPath, "/")
-	if len(parts) < 2 {
-		fmt.Println(errorStyle.Render("Error: Invalid repository format. Please use full path format (e.g., owner/repo or group/subgroup/repo)."))
+	fullPath, repoName, err := ParseRepoPath(provider, flags.RepoPath)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v.", err)))
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	repoName := parts[len(parts)-1]
-	fullPath := strings.Join(parts[:len(parts)-1], "/")
-
 	var repoInfo RepoInfo
+	repoInfo.Provider = provider.Name()
 	repoInfo.FullPath = fullPath
 	repoInfo.RepoName = repoName
-	repoInfo.Token = flags.Token
 	repoInfo.DryRun = flags.DryRun
+	repoInfo.GitHubURL = flags.GitHubURL
+	repoInfo.GitLabURL = flags.GitLabURL
+	repoInfo.GiteaURL = flags.GiteaURL
+	repoInfo.BitbucketURL = flags.BitbucketURL
+	repoInfo.AzureURL = flags.AzureURL
+	repoInfo.Transport = Transport(strings.ToLower(flags.Transport))
+	repoInfo.SSHKey = flags.SSHKey
+	repoInfo.KnownHosts = flags.KnownHosts
+	repoInfo.Branch = flags.Branch
+	repoInfo.CloneStrategy = CloneStrategy(strings.ToLower(flags.CloneStrategy))
+	repoInfo.KeepLast = flags.KeepLast
+	repoInfo.From = flags.From
+	repoInfo.KeepTags = ParseTagPatterns(flags.KeepTags)
+	repoInfo.DeleteTags = ParseTagPatterns(flags.DeleteTags)
+	repoInfo.Retag = ParseTagPatterns(flags.Retag)
+	repoInfo.KeepLatestRelease = flags.KeepLatestRelease
+	repoInfo.ArchiveDir = flags.ArchiveDir
+	repoInfo.Backup = flags.Backup
+	repoInfo.BackupRemote = flags.BackupRemote
+
+	if repoInfo.Transport != TransportHTTPS && repoInfo.Transport != TransportSSH {
+		fmt.Println(errorStyle.Render("Error: Invalid transport. Use 'https' or 'ssh'."))
+		os.Exit(1)
+	}
+
+	if !slices.Contains(CloneStrategies(), string(repoInfo.CloneStrategy)) {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: Invalid clone strategy. Use one of: %s.", strings.Join(CloneStrategies(), ", "))))
+		os.Exit(1)
+	}
+
+	if flags.KeepLast < 0 {
+		fmt.Println(errorStyle.Render("Error: --keep-last cannot be negative."))
+		os.Exit(1)
+	}
+	if flags.SquashBefore != "" {
+		if flags.KeepLast > 0 {
+			fmt.Println(errorStyle.Render("Error: --keep-last and --squash-before cannot be used together."))
+			os.Exit(1)
+		}
+		if repoInfo.SquashBefore, err = ParseCutoffDate(flags.SquashBefore); err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			os.Exit(1)
+		}
+	}
+	if (repoInfo.KeepLast > 0 || !repoInfo.SquashBefore.IsZero()) && repoInfo.From != "" {
+		fmt.Println(errorStyle.Render("Error: --from cannot be used with --keep-last or --squash-before."))
+		os.Exit(1)
+	}
+	if (repoInfo.KeepLast > 0 || !repoInfo.SquashBefore.IsZero()) && repoInfo.CloneStrategy == CloneShallow {
+		fmt.Println(errorStyle.Render("Error: --clone-strategy shallow cannot be used with --keep-last or --squash-before."))
+		os.Exit(1)
+	}
+
+	if _, err := NewTagFilter(repoInfo.KeepTags, repoInfo.DeleteTags); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+	if _, err := NewRetagSelector(repoInfo.Retag); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+	if _, ok := provider.(ReleaseKeeper); repoInfo.KeepLatestRelease && !ok {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: --keep-latest-release is not supported for %s.", provider.Name())))
+		os.Exit(1)
+	}
+	if _, ok := provider.(ReleaseKeeper); repoInfo.ArchiveDir != "" && !ok {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: --archive-dir is not supported for %s.", provider.Name())))
+		os.Exit(1)
+	}
 
-	switch strings.ToLower(flags.Provider) {
-	case "github":
-		repoInfo.Provider = GitHub
-	case "gitlab":
-		repoInfo.Provider = GitLab
-		repoInfo.GitLabURL = flags.GitLabURL
-	default:
-		fmt.Println(errorStyle.Render("Error: Invalid provider. Use 'github' or 'gitlab'."))
+	token, tokenSource, err := ResolveToken(provider, repoInfo, flags)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+	if token == "" && provider.Capabilities().RequiresToken {
+		fmt.Println(errorStyle.Render("Error: Missing access token. Use --token, --token-file, the " +
+			strings.ToUpper(string(provider.Name())) + "_TOKEN or " + tokenEnvVar + " environment variable, or a git credential helper."))
+		flag.Usage()
 		os.Exit(1)
 	}
+	if token != "" {
+		fmt.Println(info.Render(fmt.Sprintf("Using access token from %s", tokenSource)))
+	}
+	repoInfo.Token = token
+
+	if restore {
+		runRestore(repoInfo, flags)
+		return
+	}
 
 	var commitMessage string
 
@@ -136,10 +329,15 @@ func main() {
 		fmt.Printf(info.Render("Using default commit message: '%s'\n"), commitMessage)
 	}
 
+	commits := describeCommits(flags.Branch, repoInfo.KeepLast, repoInfo.SquashBefore)
+	if repoInfo.From != "" {
+		commits += fmt.Sprintf(" into the content of %s", repoInfo.From)
+	}
+
 	// Show confirmation unless in non-interactive mode
 	if !flags.NoInteractive {
 		// Show confirmation prompt
-		confirmed, err := PromptConfirmation(flags.DryRun)
+		confirmed, err := PromptConfirmation(flags.DryRun, commits)
 		if err != nil {
 			fmt.Println(errorStyle.Render(fmt.Sprintf("Error during confirmation: %v", err)))
 			os.Exit(1)
@@ -165,9 +363,9 @@ func main() {
 	} else {
 		// Still show what's going to happen in non-interactive mode
 		if flags.DryRun {
-			fmt.Println(warning.Render("DRY RUN: Will simulate squashing all commits on main branch"))
+			fmt.Println(warning.Render(fmt.Sprintf("DRY RUN: Will simulate squashing %s", commits)))
 		} else {
-			fmt.Println(warning.Render("WARNING: Will squash all commits on main branch (no interactive confirmation requested)"))
+			fmt.Println(warning.Render(fmt.Sprintf("WARNING: Will squash %s (no interactive confirmation requested)", commits)))
 		}
 	}
 
@@ -179,8 +377,50 @@ func main() {
 	if flags.DryRun {
 		fmt.Println(info.Render("\nDry run completed. No changes were pushed to remote."))
 	} else {
-		fmt.Println(success.Render(fmt.Sprintf("\nRepository %s/%s has been reset with message: '%s'",
-			repoInfo.FullPath, repoInfo.RepoName, commitMessage)))
-		fmt.Println(success.Render("All tags and releases have been deleted."))
+		fmt.Println(success.Render(fmt.Sprintf("\nRepository %s has been reset with message: '%s'",
+			flags.RepoPath, commitMessage)))
+		tags := "All tags"
+		if len(repoInfo.KeepTags) > 0 || len(repoInfo.DeleteTags) > 0 {
+			tags = "Tags selected by the tag filters"
+		}
+		if repoInfo.KeepLatestRelease {
+			fmt.Println(success.Render(tags + " and all releases but the latest have been deleted."))
+		} else if provider.Capabilities().Releases {
+			fmt.Println(success.Render(tags + " and all releases have been deleted."))
+		} else {
+			fmt.Println(success.Render(tags + " have been deleted."))
+		}
+	}
+}
+
+// runRestore force-pushes the refs of a backup bundle back to the remote
+func runRestore(repoInfo RepoInfo, flags CommandLineFlags) {
+	if flags.Bundle == "" {
+		fmt.Println(errorStyle.Render("Error: Missing --bundle for the restore command."))
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if !flags.NoInteractive {
+		confirmed, err := PromptRestoreConfirmation(flags.DryRun, flags.Bundle)
+		if err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Error during confirmation: %v", err)))
+			os.Exit(1)
+		}
+		if !confirmed {
+			fmt.Println(info.Render("Operation cancelled by user"))
+			os.Exit(0)
+		}
+	}
+
+	if err := RestoreRepo(repoInfo, flags.Bundle); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+
+	if flags.DryRun {
+		fmt.Println(info.Render("\nDry run completed. No changes were pushed to remote."))
+	} else {
+		fmt.Println(success.Render(fmt.Sprintf("\nRepository %s has been restored from %s", flags.RepoPath, flags.Bundle)))
 	}
 }
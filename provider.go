@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Provider is implemented by every supported Git hosting service
+type Provider interface {
+	// Name returns the name the provider is registered under
+	Name() GitProvider
+	// Capabilities reports which optional operations the provider supports
+	Capabilities() Capabilities
+	// CloneURL returns the URL the repository is cloned from and pushed to
+	CloneURL(repoInfo RepoInfo) string
+	// GitCredentials returns the username and password git authenticates with over HTTPS
+	GitCredentials(repoInfo RepoInfo) (username, password string)
+	// DefaultBranch looks up the default branch of the repository
+	DefaultBranch(repoInfo RepoInfo) (string, error)
+	// ListReleases returns every release of the repository
+	ListReleases(repoInfo RepoInfo) ([]Release, error)
+	// DeleteRelease deletes a single release
+	DeleteRelease(repoInfo RepoInfo, release Release) error
+}
+
+// TagDeleter is implemented by providers that delete remote tags through their
+// API instead of a git push
+type TagDeleter interface {
+	DeleteTag(repoInfo RepoInfo, tag string) error
+}
+
+// RepoPathParser is implemented by providers whose repository paths do not
+// follow the default owner/repo or group/subgroup/repo form
+type RepoPathParser interface {
+	ParseRepoPath(repoPath string) (fullPath, repoName string, err error)
+}
+
+// ReleaseKeeper is implemented by providers that can save a release, with its
+// notes and assets, and publish it again once the history has been reset
+type ReleaseKeeper interface {
+	// LatestRelease returns the latest release, or nil when there is none
+	LatestRelease(repoInfo RepoInfo) (*Release, error)
+	// SaveRelease fetches the notes of a release and downloads its assets into dir
+	SaveRelease(repoInfo RepoInfo, release Release, dir string) (SavedRelease, error)
+	// RestoreRelease publishes a saved release again on its tag
+	RestoreRelease(repoInfo RepoInfo, saved SavedRelease) error
+}
+
+var providers = map[GitProvider]Provider{}
+
+// RegisterProvider makes a provider available under its name
+func RegisterProvider(provider Provider) {
+	providers[provider.Name()] = provider
+}
+
+// GetProvider returns the provider registered under the given name
+func GetProvider(name GitProvider) (Provider, error) {
+	provider, ok := providers[GitProvider(strings.ToLower(string(name)))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported git provider '%s' (use one of: %s)", name, strings.Join(ProviderNames(), ", "))
+	}
+	return provider, nil
+}
+
+// ProviderNames returns the sorted names of all registered providers
+func ProviderNames() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseRepoPath splits a repository path into its namespace and repository name
+func ParseRepoPath(provider Provider, repoPath string) (string, string, error) {
+	if parser, ok := provider.(RepoPathParser); ok {
+		return parser.ParseRepoPath(repoPath)
+	}
+
+	parts := strings.Split(repoPath, "/")
+	if len(parts) < 2 {
+		return "", "", fmt.Errorf("invalid repository format, please use full path format (e.g., owner/repo or group/subgroup/repo)")
+	}
+	return strings.Join(parts[:len(parts)-1], "/"), parts[len(parts)-1], nil
+}
+
+// DeleteReleases deletes every release of the repository, or lists them in dry-run mode
+func DeleteReleases(provider Provider, repoInfo RepoInfo) error {
+	releases, err := provider.ListReleases(repoInfo)
+	if err != nil {
+		return err
+	}
+
+	if len(releases) == 0 {
+		fmt.Println(info.Render("No releases found to delete"))
+		return nil
+	}
+
+	fmt.Println(info.Render(fmt.Sprintf("Found %d releases", len(releases))))
+
+	if repoInfo.DryRun {
+		fmt.Println(info.Render("\nThe following releases would be deleted:"))
+		for _, release := range releases {
+			fmt.Println(info.Render(fmt.Sprintf("- Release %s", releaseLabel(release))))
+		}
+		return nil
+	}
+
+	deleted := 0
+	for _, release := range releases {
+		if err := provider.DeleteRelease(repoInfo, release); err != nil {
+			fmt.Println(warning.Render(fmt.Sprintf("Warning: Failed to delete release %s: %v", releaseLabel(release), err)))
+		} else {
+			fmt.Println(success.Render(fmt.Sprintf("Deleted release %s", releaseLabel(release))))
+			deleted++
+		}
+	}
+
+	if deleted == len(releases) {
+		fmt.Println(success.Render(fmt.Sprintf("Deleted all %d releases", deleted)))
+	} else {
+		fmt.Println(warning.Render(fmt.Sprintf("Deleted %d of %d releases, %d failed", deleted, len(releases), len(releases)-deleted)))
+	}
+
+	return nil
+}
+
+// releaseLabel describes a release by name, prefixed with its ID and followed by
+// its tag when the provider has them
+func releaseLabel(release Release) string {
+	label := release.Name
+	if release.ID != 0 {
+		label = fmt.Sprintf("%d: %s", release.ID, release.Name)
+	}
+	if release.TagName != "" {
+		label += fmt.Sprintf(" (tag: %s)", release.TagName)
+	}
+	return label
+}
+
+// SaveLatestRelease downloads the latest release into dir, along with a
+// release.json holding its notes, so that it can be recreated after the reset.
+// It returns nil when the repository has no release.
+func SaveLatestRelease(provider Provider, repoInfo RepoInfo, dir string) (*SavedRelease, error) {
+	keeper, ok := provider.(ReleaseKeeper)
+	if !ok {
+		return nil, fmt.Errorf("keeping the latest release is not supported for %s", provider.Name())
+	}
+
+	latest, err := keeper.LatestRelease(repoInfo)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		fmt.Println(info.Render("No release found to keep"))
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create release directory: %v", err)
+	}
+
+	fmt.Println(info.Render(fmt.Sprintf("Saving latest release %s", releaseLabel(*latest))))
+	saved, err := keeper.SaveRelease(repoInfo, *latest, dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeReleaseJSON(saved, dir); err != nil {
+		return nil, fmt.Errorf("failed to write release %s: %v", releaseLabel(*latest), err)
+	}
+	for _, asset := range saved.Assets {
+		fmt.Println(info.Render(fmt.Sprintf("- Asset %s", asset.Name)))
+	}
+	return &saved, nil
+}
+
+// RestoreRelease publishes a saved release again, or describes it in dry-run mode
+func RestoreRelease(provider Provider, repoInfo RepoInfo, saved SavedRelease) error {
+	if repoInfo.DryRun {
+		fmt.Println(info.Render(fmt.Sprintf("\nWould recreate release %s with %d assets", releaseLabel(saved.Release), len(saved.Assets))))
+		return nil
+	}
+
+	keeper, ok := provider.(ReleaseKeeper)
+	if !ok {
+		return fmt.Errorf("keeping the latest release is not supported for %s", provider.Name())
+	}
+	if err := keeper.RestoreRelease(repoInfo, saved); err != nil {
+		return err
+	}
+	fmt.Println(success.Render(fmt.Sprintf("Recreated release %s", releaseLabel(saved.Release))))
+	return nil
+}
+
+// writeFile stores the content of r at path
+func writeFile(path string, r io.Reader) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	azureAPIVersion = "7.0"
+	azureCloudHost  = "dev.azure.com"
+	azureZeroObject = "0000000000000000000000000000000000000000"
+)
+
+// AzureProvider handles Azure DevOps Repos, addressed as org/project/repo.
+// Azure Repos have no releases, so only tags are cleaned up through the API.
+type AzureProvider struct{}
+
+func init() {
+	RegisterProvider(AzureProvider{})
+}
+
+type azureRepository struct {
+	DefaultBranch string `json:"defaultBranch"`
+}
+
+type azureRef struct {
+	Name     string `json:"name"`
+	ObjectID string `json:"objectId"`
+}
+
+type azureRefs struct {
+	Value []azureRef `json:"value"`
+}
+
+type azureRefUpdate struct {
+	Name        string `json:"name"`
+	OldObjectID string `json:"oldObjectId"`
+	NewObjectID string `json:"newObjectId"`
+}
+
+type azureRefUpdateResults struct {
+	Value []struct {
+		Name          string `json:"name"`
+		Success       bool   `json:"success"`
+		UpdateStatus  string `json:"updateStatus"`
+		CustomMessage string `json:"customMessage"`
+	} `json:"value"`
+}
+
+func (AzureProvider) Name() GitProvider {
+	return Azure
+}
+
+func (AzureProvider) Capabilities() Capabilities {
+	return Capabilities{Releases: false, DefaultBranch: true, RequiresToken: true}
+}
+
+// ParseRepoPath requires the org/project/repo form used by Azure DevOps
+func (AzureProvider) ParseRepoPath(repoPath string) (string, string, error) {
+	parts := strings.Split(repoPath, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", fmt.Errorf("azure repositories must be given as org/project/repo")
+	}
+	return parts[0] + "/" + parts[1], parts[2], nil
+}
+
+func (AzureProvider) CloneURL(repoInfo RepoInfo) string {
+	org, project := azureOrgProject(repoInfo)
+	return fmt.Sprintf("%s/%s/%s/_git/%s", strings.TrimSuffix(repoInfo.AzureURL, "/"),
+		url.PathEscape(org), url.PathEscape(project), url.PathEscape(repoInfo.RepoName))
+}
+
+// SSHCloneURL follows the v3 SSH path scheme of Azure DevOps Services, or the
+// _git path of Azure DevOps Server
+func (AzureProvider) SSHCloneURL(repoInfo RepoInfo) string {
+	org, project := azureOrgProject(repoInfo)
+
+	parsed, err := url.Parse(repoInfo.AzureURL)
+	if err != nil || parsed.Hostname() == "" || strings.EqualFold(parsed.Hostname(), azureCloudHost) {
+		return fmt.Sprintf("git@ssh.%s:v3/%s/%s/%s", azureCloudHost,
+			url.PathEscape(org), url.PathEscape(project), url.PathEscape(repoInfo.RepoName))
+	}
+	return fmt.Sprintf("ssh://%s%s/%s/%s/_git/%s", parsed.Hostname(), strings.TrimSuffix(parsed.Path, "/"),
+		url.PathEscape(org), url.PathEscape(project), url.PathEscape(repoInfo.RepoName))
+}
+
+// GitCredentials uses the PAT as the password; Azure DevOps ignores the username
+func (AzureProvider) GitCredentials(repoInfo RepoInfo) (string, string) {
+	return "pat", repoInfo.Token
+}
+
+func (AzureProvider) DefaultBranch(repoInfo RepoInfo) (string, error) {
+	var repo azureRepository
+	path := fmt.Sprintf("%s?api-version=%s", azureRepoPath(repoInfo), azureAPIVersion)
+	if _, err := azureClient(repoInfo).Do(http.MethodGet, path, nil, &repo); err != nil {
+		return "", fmt.Errorf("failed to get repository: %v", err)
+	}
+	return strings.TrimPrefix(repo.DefaultBranch, "refs/heads/"), nil
+}
+
+func (AzureProvider) ListReleases(repoInfo RepoInfo) ([]Release, error) {
+	return nil, nil
+}
+
+func (AzureProvider) DeleteRelease(repoInfo RepoInfo, release Release) error {
+	return fmt.Errorf("azure repos do not support releases")
+}
+
+// DeleteTag looks up the object the tag points at, then updates the ref to the
+// zero object, which is how the refs API expresses a deletion
+func (AzureProvider) DeleteTag(repoInfo RepoInfo, tag string) error {
+	client := azureClient(repoInfo)
+	refName := "refs/tags/" + tag
+
+	var refs azureRefs
+	path := fmt.Sprintf("%s/refs?filter=%s&api-version=%s", azureRepoPath(repoInfo), url.QueryEscape("tags/"+tag), azureAPIVersion)
+	if _, err := client.Do(http.MethodGet, path, nil, &refs); err != nil {
+		return fmt.Errorf("failed to look up tag: %v", err)
+	}
+
+	var objectID string
+	for _, ref := range refs.Value {
+		if ref.Name == refName {
+			objectID = ref.ObjectID
+			break
+		}
+	}
+	if objectID == "" {
+		return fmt.Errorf("tag %s not found on remote", tag)
+	}
+
+	var results azureRefUpdateResults
+	update := []azureRefUpdate{{Name: refName, OldObjectID: objectID, NewObjectID: azureZeroObject}}
+	path = fmt.Sprintf("%s/refs?api-version=%s", azureRepoPath(repoInfo), azureAPIVersion)
+	if _, err := client.Do(http.MethodPost, path, update, &results); err != nil {
+		return fmt.Errorf("failed to delete tag: %v", err)
+	}
+
+	for _, result := range results.Value {
+		if !result.Success {
+			return fmt.Errorf("failed to delete tag: %s %s", result.UpdateStatus, result.CustomMessage)
+		}
+	}
+	return nil
+}
+
+func azureOrgProject(repoInfo RepoInfo) (string, string) {
+	parts := strings.SplitN(repoInfo.FullPath, "/", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// azureClient authenticates with the PAT as the password of an empty username
+func azureClient(repoInfo RepoInfo) RESTClient {
+	credentials := base64.StdEncoding.EncodeToString([]byte(":" + repoInfo.Token))
+	return RESTClient{
+		BaseURL: strings.TrimSuffix(repoInfo.AzureURL, "/"),
+		Header:  http.Header{"Authorization": []string{"Basic " + credentials}},
+	}
+}
+
+func azureRepoPath(repoInfo RepoInfo) string {
+	org, project := azureOrgProject(repoInfo)
+	return fmt.Sprintf("/%s/%s/_apis/git/repositories/%s",
+		url.PathEscape(org), url.PathEscape(project), url.PathEscape(repoInfo.RepoName))
+}
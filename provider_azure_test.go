@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAzureParseRepoPath(t *testing.T) {
+	testCases := []struct {
+		name         string
+		repoPath     string
+		expectedPath string
+		expectedRepo string
+		expectError  bool
+	}{
+		{
+			name:         "Org, project and repo",
+			repoPath:     "org/project/repo",
+			expectedPath: "org/project",
+			expectedRepo: "repo",
+		},
+		{
+			name:        "Missing project",
+			repoPath:    "org/repo",
+			expectError: true,
+		},
+		{
+			name:        "Too many parts",
+			repoPath:    "org/project/group/repo",
+			expectError: true,
+		},
+	}
+
+	provider, err := GetProvider(Azure)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fullPath, repoName, err := ParseRepoPath(provider, tc.repoPath)
+
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if fullPath != tc.expectedPath || repoName != tc.expectedRepo {
+				t.Errorf("Expected %s and %s, got %s and %s", tc.expectedPath, tc.expectedRepo, fullPath, repoName)
+			}
+		})
+	}
+}
+
+func TestAzureProvider(t *testing.T) {
+	var updates []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(":token"))
+		if r.Header.Get("Authorization") != expectedAuth {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/org/My Project/_apis/git/repositories/repo":
+			json.NewEncoder(w).Encode(map[string]string{"defaultBranch": "refs/heads/trunk"})
+		case r.Method == http.MethodGet && r.URL.Path == "/org/My Project/_apis/git/repositories/repo/refs":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"value": []map[string]string{
+					{"name": "refs/tags/v1", "objectId": "1111111111111111111111111111111111111111"},
+					{"name": "refs/tags/v10", "objectId": "2222222222222222222222222222222222222222"},
+				},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/org/My Project/_apis/git/repositories/repo/refs":
+			json.NewDecoder(r.Body).Decode(&updates)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"value": []map[string]interface{}{{"name": "refs/tags/v1", "success": true}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repoInfo := RepoInfo{
+		Provider: Azure,
+		FullPath: "org/My Project",
+		RepoName: "repo",
+		Token:    "token",
+		AzureURL: server.URL,
+	}
+
+	provider, err := GetProvider(Azure)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cloneURL := provider.CloneURL(repoInfo); cloneURL != server.URL+"/org/My%20Project/_git/repo" {
+		t.Errorf("Unexpected clone URL %s", cloneURL)
+	}
+
+	branch, err := provider.DefaultBranch(repoInfo)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if branch != "trunk" {
+		t.Errorf("Expected default branch trunk, got %s", branch)
+	}
+
+	if err := provider.(TagDeleter).DeleteTag(repoInfo, "v1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(updates) != 1 {
+		t.Fatalf("Expected 1 ref update, got %d", len(updates))
+	}
+	if updates[0]["name"] != "refs/tags/v1" || updates[0]["oldObjectId"] != "1111111111111111111111111111111111111111" {
+		t.Errorf("Unexpected ref update %v", updates[0])
+	}
+	if updates[0]["newObjectId"] != "0000000000000000000000000000000000000000" {
+		t.Errorf("Expected tag to be updated to the zero object, got %s", updates[0]["newObjectId"])
+	}
+}
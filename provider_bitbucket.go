@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	bitbucketCloudHost     = "bitbucket.org"
+	bitbucketCloudPageSize = 100
+	bitbucketServerSSHPort = "7999"
+)
+
+// BitbucketProvider handles repositories hosted on Bitbucket Cloud, or on a
+// Bitbucket Server / Data Center instance when BitbucketURL points elsewhere.
+// Bitbucket has no releases, so the repository downloads are cleaned up instead
+// (Bitbucket Cloud only).
+type BitbucketProvider struct{}
+
+func init() {
+	RegisterProvider(BitbucketProvider{})
+}
+
+type bitbucketCloudRepository struct {
+	MainBranch struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+}
+
+type bitbucketCloudDownloads struct {
+	Values []struct {
+		Name string `json:"name"`
+	} `json:"values"`
+	Next string `json:"next"`
+}
+
+type bitbucketServerBranch struct {
+	DisplayID string `json:"displayId"`
+}
+
+func (BitbucketProvider) Name() GitProvider {
+	return Bitbucket
+}
+
+func (BitbucketProvider) Capabilities() Capabilities {
+	return Capabilities{Releases: true, DefaultBranch: true, RequiresToken: true}
+}
+
+func (BitbucketProvider) CloneURL(repoInfo RepoInfo) string {
+	baseURL := strings.TrimSuffix(repoInfo.BitbucketURL, "/")
+	if isBitbucketCloud(repoInfo) {
+		return fmt.Sprintf("%s/%s/%s.git", baseURL, repoInfo.FullPath, repoInfo.RepoName)
+	}
+	return fmt.Sprintf("%s/scm/%s/%s.git", baseURL, repoInfo.FullPath, repoInfo.RepoName)
+}
+
+// SSHCloneURL uses the dedicated SSH port of Bitbucket Data Center, where
+// repositories are served without the /scm prefix
+func (BitbucketProvider) SSHCloneURL(repoInfo RepoInfo) string {
+	if isBitbucketCloud(repoInfo) {
+		return fmt.Sprintf("git@%s:%s/%s.git", bitbucketCloudHost, repoInfo.FullPath, repoInfo.RepoName)
+	}
+
+	host := repoInfo.BitbucketURL
+	if parsed, err := url.Parse(repoInfo.BitbucketURL); err == nil && parsed.Hostname() != "" {
+		host = parsed.Hostname()
+	}
+	return fmt.Sprintf("ssh://git@%s:%s/%s/%s.git", host, bitbucketServerSSHPort,
+		strings.ToLower(repoInfo.FullPath), repoInfo.RepoName)
+}
+
+// GitCredentials splits "username:app-password" tokens, and otherwise uses the
+// access token as the password of the x-token-auth user
+func (BitbucketProvider) GitCredentials(repoInfo RepoInfo) (string, string) {
+	if username, password, ok := strings.Cut(repoInfo.Token, ":"); ok {
+		return username, password
+	}
+	return "x-token-auth", repoInfo.Token
+}
+
+func (BitbucketProvider) DefaultBranch(repoInfo RepoInfo) (string, error) {
+	client := bitbucketClient(repoInfo)
+
+	if isBitbucketCloud(repoInfo) {
+		var repo bitbucketCloudRepository
+		if _, err := client.Do(http.MethodGet, bitbucketRepoPath(repoInfo), nil, &repo); err != nil {
+			return "", fmt.Errorf("failed to get repository: %v", err)
+		}
+		return repo.MainBranch.Name, nil
+	}
+
+	var branch bitbucketServerBranch
+	if _, err := client.Do(http.MethodGet, bitbucketRepoPath(repoInfo)+"/branches/default", nil, &branch); err != nil {
+		return "", fmt.Errorf("failed to get default branch: %v", err)
+	}
+	return branch.DisplayID, nil
+}
+
+// ListReleases returns the repository downloads, which Bitbucket Server does not have
+func (BitbucketProvider) ListReleases(repoInfo RepoInfo) ([]Release, error) {
+	if !isBitbucketCloud(repoInfo) {
+		return nil, nil
+	}
+
+	client := bitbucketClient(repoInfo)
+
+	var result []Release
+	for page := 1; ; page++ {
+		var downloads bitbucketCloudDownloads
+		path := fmt.Sprintf("%s/downloads?page=%d&pagelen=%d", bitbucketRepoPath(repoInfo), page, bitbucketCloudPageSize)
+		if _, err := client.Do(http.MethodGet, path, nil, &downloads); err != nil {
+			return nil, fmt.Errorf("failed to list downloads: %v", err)
+		}
+
+		for _, download := range downloads.Values {
+			result = append(result, Release{Name: download.Name})
+		}
+
+		if downloads.Next == "" {
+			return result, nil
+		}
+	}
+}
+
+func (BitbucketProvider) DeleteRelease(repoInfo RepoInfo, release Release) error {
+	path := fmt.Sprintf("%s/downloads/%s", bitbucketRepoPath(repoInfo), url.PathEscape(release.Name))
+	_, err := bitbucketClient(repoInfo).Do(http.MethodDelete, path, nil, nil)
+	return err
+}
+
+func (BitbucketProvider) DeleteTag(repoInfo RepoInfo, tag string) error {
+	var path string
+	if isBitbucketCloud(repoInfo) {
+		path = fmt.Sprintf("%s/refs/tags/%s", bitbucketRepoPath(repoInfo), url.PathEscape(tag))
+	} else {
+		path = fmt.Sprintf("/rest/git/1.0/projects/%s/repos/%s/tags/%s",
+			url.PathEscape(repoInfo.FullPath), url.PathEscape(repoInfo.RepoName), url.PathEscape(tag))
+	}
+	_, err := bitbucketClient(repoInfo).Do(http.MethodDelete, path, nil, nil)
+	return err
+}
+
+func isBitbucketCloud(repoInfo RepoInfo) bool {
+	parsed, err := url.Parse(repoInfo.BitbucketURL)
+	return err == nil && strings.EqualFold(parsed.Host, bitbucketCloudHost)
+}
+
+// bitbucketClient authenticates with a bearer token, or with basic auth when
+// the token is given as "username:app-password"
+func bitbucketClient(repoInfo RepoInfo) RESTClient {
+	var baseURL string
+	if isBitbucketCloud(repoInfo) {
+		baseURL = "https://api." + bitbucketCloudHost + "/2.0"
+	} else {
+		baseURL = strings.TrimSuffix(repoInfo.BitbucketURL, "/")
+	}
+
+	authorization := "Bearer " + repoInfo.Token
+	if strings.Contains(repoInfo.Token, ":") {
+		authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(repoInfo.Token))
+	}
+
+	return RESTClient{
+		BaseURL: baseURL,
+		Header:  http.Header{"Authorization": []string{authorization}},
+	}
+}
+
+func bitbucketRepoPath(repoInfo RepoInfo) string {
+	if isBitbucketCloud(repoInfo) {
+		return fmt.Sprintf("/repositories/%s/%s", url.PathEscape(repoInfo.FullPath), url.PathEscape(repoInfo.RepoName))
+	}
+	return fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s", url.PathEscape(repoInfo.FullPath), url.PathEscape(repoInfo.RepoName))
+}
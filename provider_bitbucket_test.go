@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBitbucketProviderCloneURL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		repoInfo RepoInfo
+		expected string
+	}{
+		{
+			name: "Bitbucket Cloud",
+			repoInfo: RepoInfo{
+				FullPath:     "workspace",
+				RepoName:     "repo",
+				BitbucketURL: "https://bitbucket.org",
+			},
+			expected: "https://bitbucket.org/workspace/repo.git",
+		},
+		{
+			name: "Bitbucket Data Center",
+			repoInfo: RepoInfo{
+				FullPath:     "PROJ",
+				RepoName:     "repo",
+				BitbucketURL: "https://bitbucket.company.com/",
+			},
+			expected: "https://bitbucket.company.com/scm/PROJ/repo.git",
+		},
+	}
+
+	provider, err := GetProvider(Bitbucket)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cloneURL := provider.CloneURL(tc.repoInfo)
+			if cloneURL != tc.expected {
+				t.Errorf("Expected clone URL %s, got %s", tc.expected, cloneURL)
+			}
+		})
+	}
+}
+
+func TestBitbucketServerProvider(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/1.0/projects/PROJ/repos/repo/branches/default":
+			json.NewEncoder(w).Encode(map[string]string{"id": "refs/heads/master", "displayId": "master"})
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repoInfo := RepoInfo{
+		Provider:     Bitbucket,
+		FullPath:     "PROJ",
+		RepoName:     "repo",
+		Token:        "token",
+		BitbucketURL: server.URL,
+	}
+
+	provider, err := GetProvider(Bitbucket)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	branch, err := provider.DefaultBranch(repoInfo)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if branch != "master" {
+		t.Errorf("Expected default branch master, got %s", branch)
+	}
+
+	releases, err := provider.ListReleases(repoInfo)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(releases) != 0 {
+		t.Errorf("Expected no downloads on Bitbucket Data Center, got %d", len(releases))
+	}
+
+	if err := provider.(TagDeleter).DeleteTag(repoInfo, "v1.0.0"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "/rest/git/1.0/projects/PROJ/repos/repo/tags/v1.0.0" {
+		t.Errorf("Expected tag deletion through the git REST API, got %v", deleted)
+	}
+}
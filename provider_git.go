@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// GitRemoteProvider handles any git remote (ssh://, https://, file:// or a
+// local path) without a forge API: there are no releases to clean up and tags
+// are deleted with git push. The repository path is the remote URL itself.
+type GitRemoteProvider struct{}
+
+func init() {
+	RegisterProvider(GitRemoteProvider{})
+}
+
+func (GitRemoteProvider) Name() GitProvider {
+	return GitRemote
+}
+
+func (GitRemoteProvider) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+// ParseRepoPath keeps the whole remote URL as the full path and derives the
+// repository name the same way git names the clone directory. Local paths are
+// made absolute, the clone runs from a temporary directory.
+func (GitRemoteProvider) ParseRepoPath(repoPath string) (string, string, error) {
+	name := strings.TrimSuffix(repoPath, "/")
+	name = strings.TrimSuffix(name, "/.git")
+	name = strings.TrimSuffix(name, ".git")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	if name == "" {
+		return "", "", fmt.Errorf("invalid remote URL '%s'", repoPath)
+	}
+
+	if isLocalPath(repoPath) {
+		path, err := filepath.Abs(repoPath)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to resolve remote path '%s': %v", repoPath, err)
+		}
+		return path, name, nil
+	}
+	return repoPath, name, nil
+}
+
+// isLocalPath tells an on-disk remote from a URL or an scp-like host:path, the
+// way git does: a colon before any slash introduces a host
+func isLocalPath(remote string) bool {
+	if strings.Contains(remote, "://") {
+		return false
+	}
+	colon := strings.Index(remote, ":")
+	return colon < 0 || strings.Contains(remote[:colon], "/")
+}
+
+func (GitRemoteProvider) CloneURL(repoInfo RepoInfo) string {
+	return repoInfo.FullPath
+}
+
+// SSHCloneURL returns the remote URL unchanged, whatever its scheme
+func (GitRemoteProvider) SSHCloneURL(repoInfo RepoInfo) string {
+	return repoInfo.FullPath
+}
+
+// GitCredentials passes the token, if any, as the password for HTTPS remotes
+func (GitRemoteProvider) GitCredentials(repoInfo RepoInfo) (string, string) {
+	return "git", repoInfo.Token
+}
+
+func (GitRemoteProvider) DefaultBranch(repoInfo RepoInfo) (string, error) {
+	return "", fmt.Errorf("git remotes have no API to look up the default branch")
+}
+
+func (GitRemoteProvider) ListReleases(repoInfo RepoInfo) ([]Release, error) {
+	return nil, nil
+}
+
+func (GitRemoteProvider) DeleteRelease(repoInfo RepoInfo, release Release) error {
+	return fmt.Errorf("git remotes do not support releases")
+}
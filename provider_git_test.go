@@ -0,0 +1,487 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// git runs a git command in dir and returns its trimmed output
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// setupBareRepo creates a bare repository with a few commits and tags on branch
+func setupBareRepo(t *testing.T, branch string) string {
+	t.Helper()
+
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	root := t.TempDir()
+	bare := filepath.Join(root, "repo.git")
+	work := filepath.Join(root, "work")
+
+	git(t, root, "init", "--bare", "--initial-branch="+branch, bare)
+	git(t, root, "clone", bare, work)
+
+	for i, content := range []string{"one", "two", "three"} {
+		if err := os.WriteFile(filepath.Join(work, "file.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git(t, work, "add", "file.txt")
+		git(t, work, "commit", "-m", "commit "+content)
+		git(t, work, "tag", "v1."+string(rune('0'+i)))
+	}
+	git(t, work, "push", "origin", branch, "--tags")
+
+	return bare
+}
+
+func TestGitRemoteParseRepoPath(t *testing.T) {
+	testCases := []struct {
+		remote   string
+		expected string
+	}{
+		{"ssh://git@example.com/srv/repo.git", "repo"},
+		{"https://example.com/group/repo", "repo"},
+		{"file:///srv/git/repo.git/", "repo"},
+		{"/srv/git/repo/.git", "repo"},
+		{"git@example.com:repo.git", "repo"},
+	}
+
+	provider, err := GetProvider(GitRemote)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.remote, func(t *testing.T) {
+			fullPath, repoName, err := ParseRepoPath(provider, tc.remote)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if fullPath != tc.remote {
+				t.Errorf("Expected full path %s, got %s", tc.remote, fullPath)
+			}
+			if repoName != tc.expected {
+				t.Errorf("Expected repo name %s, got %s", tc.expected, repoName)
+			}
+		})
+	}
+}
+
+func TestResetRepoRelativeLocalPath(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	relative, err := filepath.Rel(cwd, bare)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := GetProvider(GitRemote)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fullPath, repoName, err := ParseRepoPath(provider, relative)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !filepath.IsAbs(fullPath) {
+		t.Errorf("Expected %s to be made absolute, got %s", relative, fullPath)
+	}
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: fullPath,
+		RepoName: repoName,
+	}
+
+	if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if count := git(t, bare, "rev-list", "--count", "main"); count != "1" {
+		t.Errorf("Expected 1 commit on main, got %s", count)
+	}
+}
+
+func TestResetRepoLocalBareRepository(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	originalTree := git(t, bare, "rev-parse", "main^{tree}")
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+	}
+
+	if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if count := git(t, bare, "rev-list", "--count", "main"); count != "1" {
+		t.Errorf("Expected 1 commit on main, got %s", count)
+	}
+	if message := git(t, bare, "log", "-1", "--format=%s", "main"); message != "fresh start" {
+		t.Errorf("Expected commit message 'fresh start', got '%s'", message)
+	}
+	if tree := git(t, bare, "rev-parse", "main^{tree}"); tree != originalTree {
+		t.Errorf("Expected tree %s to be preserved, got %s", originalTree, tree)
+	}
+	if tags := git(t, bare, "tag"); tags != "" {
+		t.Errorf("Expected all tags to be deleted, got %s", tags)
+	}
+}
+
+func TestResetRepoDetectsDefaultBranch(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "trunk")
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+	}
+
+	output := captureOutput(func() {
+		if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "Resetting branch: trunk") {
+		t.Errorf("Expected output to name the detected branch, got %s", output)
+	}
+	if count := git(t, bare, "rev-list", "--count", "trunk"); count != "1" {
+		t.Errorf("Expected 1 commit on trunk, got %s", count)
+	}
+	if branches := git(t, bare, "branch", "--format=%(refname:short)"); branches != "trunk" {
+		t.Errorf("Expected only the trunk branch on the remote, got %s", branches)
+	}
+}
+
+func TestResetRepoBranchOverride(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	work := filepath.Join(t.TempDir(), "work")
+	git(t, filepath.Dir(work), "clone", bare, work)
+	git(t, work, "checkout", "-b", "develop")
+	git(t, work, "commit", "--allow-empty", "-m", "develop only")
+	git(t, work, "push", "origin", "develop")
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+		Branch:   "develop",
+	}
+
+	if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if count := git(t, bare, "rev-list", "--count", "develop"); count != "1" {
+		t.Errorf("Expected 1 commit on develop, got %s", count)
+	}
+	if count := git(t, bare, "rev-list", "--count", "main"); count != "3" {
+		t.Errorf("Expected main to be left untouched, got %s commits", count)
+	}
+}
+
+func TestResetRepoReportsRejectedTagDeletion(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	hook := "#!/bin/sh\nif [ \"$1\" = refs/tags/v1.1 ]; then echo protected tag; exit 1; fi\n"
+	if err := os.WriteFile(filepath.Join(bare, "hooks", "update"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+	}
+
+	output := captureOutput(func() {
+		if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	if tags := git(t, bare, "tag"); tags != "v1.1" {
+		t.Errorf("Expected only the protected tag to remain, got %s", tags)
+	}
+	for _, tag := range []string{"v1.0", "v1.2"} {
+		if !strings.Contains(output, "Deleted remote tag: "+tag) {
+			t.Errorf("Expected output to report deletion of %s, got %s", tag, output)
+		}
+	}
+	if !strings.Contains(output, "Failed to delete remote tag v1.1") {
+		t.Errorf("Expected output to report the rejected tag, got %s", output)
+	}
+}
+
+func TestGetRemoteTags(t *testing.T) {
+	bare := setupBareRepo(t, "main")
+	work := filepath.Join(t.TempDir(), "work")
+	git(t, filepath.Dir(work), "clone", bare, work)
+	git(t, work, "tag", "-a", "v2.0", "-m", "annotated")
+	git(t, work, "push", "origin", "v2.0")
+	git(t, work, "tag", "-d", "v1.0")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(work); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := GetRemoteTags()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"v1.0", "v1.1", "v1.2", "v2.0"}
+	if strings.Join(tags, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected remote tags %v, got %v", expected, tags)
+	}
+}
+
+func TestResetRepoDryRunListsRemoteTags(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+		DryRun:   true,
+	}
+
+	output := captureOutput(func() {
+		if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "Would delete 3 remote tags") {
+		t.Errorf("Expected output to count the remote tags, got %s", output)
+	}
+	for _, tag := range []string{"v1.0", "v1.1", "v1.2"} {
+		if !strings.Contains(output, "- Tag "+tag) {
+			t.Errorf("Expected output to list %s, got %s", tag, output)
+		}
+	}
+	if tags := git(t, bare, "tag"); tags != "v1.0\nv1.1\nv1.2" {
+		t.Errorf("Expected remote tags to be left untouched, got %s", tags)
+	}
+}
+
+func TestResetRepoKeepTags(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+		KeepTags: []string{"v1.1"},
+		DryRun:   true,
+	}
+
+	output := captureOutput(func() {
+		if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(output, "Would keep 1 tags") || !strings.Contains(output, "Would delete 2 remote tags") {
+		t.Errorf("Expected dry run to list kept and deleted tags, got %s", output)
+	}
+
+	repoInfo.DryRun = false
+	if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if tags := git(t, bare, "tag"); tags != "v1.1" {
+		t.Errorf("Expected only v1.1 to be kept, got %s", tags)
+	}
+}
+
+func TestResetRepoRetag(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	work := filepath.Join(t.TempDir(), "work")
+	git(t, filepath.Dir(work), "clone", bare, work)
+	t.Setenv("GIT_COMMITTER_NAME", "Release Bot")
+	t.Setenv("GIT_COMMITTER_DATE", "1600000000 +0200")
+	git(t, work, "tag", "-a", "v2.0.0", "-m", "Version 2.0.0\n\nRelease notes")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	os.Unsetenv("GIT_COMMITTER_DATE")
+	git(t, work, "push", "origin", "v2.0.0")
+	originalTagger := git(t, bare, "for-each-ref", "--format=%(taggername) %(taggeremail) %(taggerdate:raw)", "refs/tags/v2.0.0")
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+		Retag:    []string{"latest", "v1.0"},
+	}
+
+	if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if tags := git(t, bare, "tag"); tags != "v1.0\nv2.0.0" {
+		t.Errorf("Expected only the retagged tags to remain, got %s", tags)
+	}
+	head := git(t, bare, "rev-parse", "main")
+	for _, tag := range []string{"v1.0", "v2.0.0"} {
+		if target := git(t, bare, "rev-parse", tag+"^{commit}"); target != head {
+			t.Errorf("Expected %s to point at the new root commit %s, got %s", tag, head, target)
+		}
+	}
+	if message := git(t, bare, "for-each-ref", "--format=%(contents)", "refs/tags/v2.0.0"); message != "Version 2.0.0\n\nRelease notes" {
+		t.Errorf("Expected the annotated tag message to be preserved, got %q", message)
+	}
+	if tagger := git(t, bare, "for-each-ref", "--format=%(taggername) %(taggeremail) %(taggerdate:raw)", "refs/tags/v2.0.0"); tagger != originalTagger {
+		t.Errorf("Expected tagger %s to be preserved, got %s", originalTagger, tagger)
+	}
+	if objectType := git(t, bare, "cat-file", "-t", "v1.0"); objectType != "commit" {
+		t.Errorf("Expected v1.0 to stay a lightweight tag, got a %s", objectType)
+	}
+}
+
+func TestResetRepoLeaseRejected(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	work := filepath.Join(t.TempDir(), "work")
+	git(t, filepath.Dir(work), "clone", bare, work)
+	git(t, work, "tag", "v2.0.0")
+	git(t, work, "push", "origin", "v2.0.0")
+	originalTags := git(t, bare, "for-each-ref", "--format=%(refname) %(objectname)", "refs/tags")
+
+	// A teammate pushes once the branch is moved to the squash commit
+	hooks := t.TempDir()
+	hook := "#!/bin/sh\n[ \"$1\" = committed ] || exit 0\n" +
+		"grep -q \" refs/heads/main$\" || exit 0\n" +
+		"git rev-parse -q --verify refs/heads/main^ >/dev/null && exit 0\n" +
+		"unset GIT_DIR GIT_INDEX_FILE GIT_WORK_TREE GIT_PREFIX\n" +
+		"git -C " + work + " -c core.hooksPath=/dev/null commit -q --allow-empty -m concurrent &&\n" +
+		"git -C " + work + " -c core.hooksPath=/dev/null push -q origin main\n"
+	if err := os.WriteFile(filepath.Join(hooks, "reference-transaction"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(t.TempDir(), "gitconfig")
+	if err := os.WriteFile(config, []byte("[core]\n\thooksPath = "+hooks+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", config)
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+		Retag:    []string{"latest"},
+	}
+
+	err := ResetRepo(repoInfo, "fresh start")
+	if err == nil || !strings.Contains(err.Error(), "moved since it was cloned") {
+		t.Fatalf("Expected the lease to be rejected, got %v", err)
+	}
+
+	if message := git(t, bare, "log", "-1", "--format=%s", "main"); message != "concurrent" {
+		t.Errorf("Expected the concurrent commit to survive, got '%s'", message)
+	}
+	if tags := git(t, bare, "for-each-ref", "--format=%(refname) %(objectname)", "refs/tags"); tags != originalTags {
+		t.Errorf("Expected tags to be left untouched, got %s", tags)
+	}
+}
+
+func TestResetRepoPreservesTreeExactly(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	work := filepath.Join(t.TempDir(), "work")
+	git(t, filepath.Dir(work), "clone", bare, work)
+	if err := os.WriteFile(filepath.Join(work, "windows.txt"), []byte("line\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, work, "add", "windows.txt")
+	git(t, work, "commit", "-m", "crlf file")
+	// Attributes added afterwards would normalize windows.txt if it were re-staged
+	if err := os.WriteFile(filepath.Join(work, ".gitattributes"), []byte("* text=auto\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, work, "add", ".gitattributes")
+	git(t, work, "commit", "-m", "attributes")
+	git(t, work, "push", "origin", "main")
+	originalTree := git(t, bare, "rev-parse", "main^{tree}")
+
+	// Staging hooks must not be able to alter the snapshot either
+	hooks := t.TempDir()
+	if err := os.WriteFile(filepath.Join(hooks, "pre-commit"), []byte("#!/bin/sh\ngit rm -q --cached file.txt\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(t.TempDir(), "gitconfig")
+	if err := os.WriteFile(config, []byte("[core]\n\thooksPath = "+hooks+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", config)
+
+	repoInfo := RepoInfo{
+		Provider: GitRemote,
+		FullPath: bare,
+		RepoName: "repo",
+	}
+
+	if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if tree := git(t, bare, "rev-parse", "main^{tree}"); tree != originalTree {
+		t.Errorf("Expected tree %s to be preserved exactly, got %s", originalTree, tree)
+	}
+	if count := git(t, bare, "rev-list", "--count", "main"); count != "1" {
+		t.Errorf("Expected 1 commit on main, got %s", count)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const giteaPageSize = 50
+
+// GiteaProvider handles repositories hosted on Gitea or Forgejo instances
+type GiteaProvider struct{}
+
+func init() {
+	RegisterProvider(GiteaProvider{})
+}
+
+type giteaRepository struct {
+	DefaultBranch string `json:"default_branch"`
+}
+
+type giteaRelease struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	TagName string `json:"tag_name"`
+}
+
+func (GiteaProvider) Name() GitProvider {
+	return Gitea
+}
+
+func (GiteaProvider) Capabilities() Capabilities {
+	return Capabilities{Releases: true, DefaultBranch: true, RequiresToken: true}
+}
+
+func (GiteaProvider) CloneURL(repoInfo RepoInfo) string {
+	return fmt.Sprintf("%s/%s/%s.git", strings.TrimSuffix(repoInfo.GiteaURL, "/"), repoInfo.FullPath, repoInfo.RepoName)
+}
+
+// GitCredentials uses the token as the password; Gitea does not check the username
+func (GiteaProvider) GitCredentials(repoInfo RepoInfo) (string, string) {
+	return "oauth2", repoInfo.Token
+}
+
+func (GiteaProvider) DefaultBranch(repoInfo RepoInfo) (string, error) {
+	var repo giteaRepository
+	if _, err := giteaClient(repoInfo).Do(http.MethodGet, giteaRepoPath(repoInfo), nil, &repo); err != nil {
+		return "", fmt.Errorf("failed to get repository: %v", err)
+	}
+	return repo.DefaultBranch, nil
+}
+
+func (GiteaProvider) ListReleases(repoInfo RepoInfo) ([]Release, error) {
+	client := giteaClient(repoInfo)
+
+	var result []Release
+	for page := 1; ; page++ {
+		var releases []giteaRelease
+		path := fmt.Sprintf("%s/releases?page=%d&limit=%d", giteaRepoPath(repoInfo), page, giteaPageSize)
+		resp, err := client.Do(http.MethodGet, path, nil, &releases)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases: %v", err)
+		}
+
+		for _, release := range releases {
+			result = append(result, Release{
+				ID:      release.ID,
+				Name:    release.Name,
+				TagName: release.TagName,
+			})
+		}
+
+		// Instances cap the page size with MAX_RESPONSE_ITEMS, so a short page is
+		// not necessarily the last one: stop at the total count or an empty page
+		total, err := strconv.Atoi(resp.Header.Get("X-Total-Count"))
+		if len(releases) == 0 || (err == nil && len(result) >= total) {
+			return result, nil
+		}
+	}
+}
+
+func (GiteaProvider) DeleteRelease(repoInfo RepoInfo, release Release) error {
+	path := fmt.Sprintf("%s/releases/%d", giteaRepoPath(repoInfo), release.ID)
+	_, err := giteaClient(repoInfo).Do(http.MethodDelete, path, nil, nil)
+	return err
+}
+
+func (GiteaProvider) DeleteTag(repoInfo RepoInfo, tag string) error {
+	path := fmt.Sprintf("%s/tags/%s", giteaRepoPath(repoInfo), url.PathEscape(tag))
+	_, err := giteaClient(repoInfo).Do(http.MethodDelete, path, nil, nil)
+	return err
+}
+
+func giteaClient(repoInfo RepoInfo) RESTClient {
+	return RESTClient{
+		BaseURL: strings.TrimSuffix(repoInfo.GiteaURL, "/") + "/api/v1",
+		Header:  http.Header{"Authorization": []string{"token " + repoInfo.Token}},
+	}
+}
+
+func giteaRepoPath(repoInfo RepoInfo) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(repoInfo.FullPath), url.PathEscape(repoInfo.RepoName))
+}
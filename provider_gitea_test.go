@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGitea serves the subset of the Gitea REST API used by the gitea provider
+type fakeGitea struct {
+	mu       sync.Mutex
+	releases int
+	maxLimit int
+	deleted  []string
+	removed  map[string]bool
+}
+
+func (f *fakeGitea) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "token token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/repos/owner/repo":
+		json.NewEncoder(w).Encode(map[string]string{"default_branch": "develop"})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/repos/owner/repo/releases":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		// Like MAX_RESPONSE_ITEMS, the instance may return less than asked for
+		if f.maxLimit > 0 && limit > f.maxLimit {
+			limit = f.maxLimit
+		}
+		var releases []map[string]interface{}
+		for id := (page-1)*limit + 1; id <= page*limit && id <= f.releases; id++ {
+			releases = append(releases, map[string]interface{}{
+				"id":       id,
+				"name":     fmt.Sprintf("Release %d", id),
+				"tag_name": fmt.Sprintf("v%d", id),
+			})
+		}
+		w.Header().Set("X-Total-Count", strconv.Itoa(f.releases))
+		json.NewEncoder(w).Encode(releases)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v1/repos/owner/repo/releases/"):
+		if f.removed == nil {
+			f.removed = make(map[string]bool)
+		}
+		f.removed[strings.TrimPrefix(r.URL.Path, "/api/v1/repos/owner/repo/releases/")] = true
+		f.deleted = append(f.deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v1/repos/owner/repo/tags/"):
+		// Release n is attached to tag vn, which cannot be deleted while it exists
+		id, ok := strings.CutPrefix(strings.TrimPrefix(r.URL.Path, "/api/v1/repos/owner/repo/tags/"), "v")
+		if n, err := strconv.Atoi(id); ok && err == nil && n <= f.releases && !f.removed[id] {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{"message": "a tag attached to a release cannot be deleted directly"})
+			return
+		}
+		f.deleted = append(f.deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestGiteaProvider(t *testing.T) {
+	fake := &fakeGitea{releases: 120}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	repoInfo := RepoInfo{
+		Provider: Gitea,
+		FullPath: "owner",
+		RepoName: "repo",
+		Token:    "token",
+		GiteaURL: server.URL,
+	}
+
+	provider, err := GetProvider(Gitea)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cloneURL := provider.CloneURL(repoInfo); cloneURL != server.URL+"/owner/repo.git" {
+		t.Errorf("Expected clone URL %s, got %s", server.URL+"/owner/repo.git", cloneURL)
+	}
+
+	branch, err := provider.DefaultBranch(repoInfo)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if branch != "develop" {
+		t.Errorf("Expected default branch develop, got %s", branch)
+	}
+
+	releases, err := provider.ListReleases(repoInfo)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(releases) != fake.releases {
+		t.Errorf("Expected %d releases across all pages, got %d", fake.releases, len(releases))
+	}
+
+	if err := provider.DeleteRelease(repoInfo, releases[0]); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	deleter, ok := provider.(TagDeleter)
+	if !ok {
+		t.Fatal("Expected gitea provider to delete tags through the API")
+	}
+	if err := deleter.DeleteTag(repoInfo, "v1"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"/api/v1/repos/owner/repo/releases/1",
+		"/api/v1/repos/owner/repo/tags/v1",
+	}
+	if len(fake.deleted) != len(expected) {
+		t.Fatalf("Expected %d delete calls, got %d", len(expected), len(fake.deleted))
+	}
+	for i, path := range expected {
+		if fake.deleted[i] != path {
+			t.Errorf("Expected DELETE %s, got %s", path, fake.deleted[i])
+		}
+	}
+}
+
+func TestGiteaListReleasesCappedPageSize(t *testing.T) {
+	fake := &fakeGitea{releases: 75, maxLimit: 30}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	provider, err := GetProvider(Gitea)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	releases, err := provider.ListReleases(RepoInfo{
+		FullPath: "owner",
+		RepoName: "repo",
+		Token:    "token",
+		GiteaURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(releases) != fake.releases {
+		t.Errorf("Expected %d releases across pages capped at %d, got %d", fake.releases, fake.maxLimit, len(releases))
+	}
+}
+
+func TestResetRepoGiteaDeletesReleasesBeforeTags(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	git(t, bare, "tag", "v1", "main")
+	git(t, bare, "tag", "v2", "main")
+	backend := gitHTTPBackend(t, bare)
+
+	fake := &fakeGitea{releases: 2}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			backend.ServeHTTP(w, r)
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	repoInfo := RepoInfo{
+		Provider: Gitea,
+		FullPath: "owner",
+		RepoName: "repo",
+		Token:    "token",
+		GiteaURL: server.URL,
+		Branch:   "main",
+	}
+
+	output := captureOutput(func() {
+		if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	if strings.Contains(output, "Failed to delete remote tag") {
+		t.Errorf("Expected every tag to be deleted, got %s", output)
+	}
+	for _, tag := range []string{"v1", "v2"} {
+		if !slices.Contains(fake.deleted, "/api/v1/repos/owner/repo/tags/"+tag) {
+			t.Errorf("Expected tag %s to be deleted once its release was gone, got %v", tag, fake.deleted)
+		}
+	}
+}
+
+func TestGiteaProviderUnauthorized(t *testing.T) {
+	server := httptest.NewServer(&fakeGitea{})
+	defer server.Close()
+
+	provider, err := GetProvider(Gitea)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err = provider.ListReleases(RepoInfo{
+		FullPath: "owner",
+		RepoName: "repo",
+		Token:    "wrong",
+		GiteaURL: server.URL,
+	})
+	if err == nil {
+		t.Error("Expected error but got none")
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v38/github"
+	"golang.org/x/oauth2"
+)
+
+const (
+	gitHubCloudURL = "https://github.com"
+	gitHubPageSize = 100
+)
+
+// For mocking in tests
+var newGitHubClient = func(token, baseURL string) (*github.Client, error) {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	tc := oauth2.NewClient(context.Background(), ts)
+	if isGitHubCloud(baseURL) {
+		return github.NewClient(tc), nil
+	}
+	// The enterprise client appends /api/v3/ and /api/uploads/ to the base URL
+	return github.NewEnterpriseClient(baseURL, baseURL, tc)
+}
+
+// GitHubProvider handles repositories hosted on github.com or on a GitHub
+// Enterprise Server instance
+type GitHubProvider struct{}
+
+func init() {
+	RegisterProvider(GitHubProvider{})
+}
+
+func (GitHubProvider) Name() GitProvider {
+	return GitHub
+}
+
+func (GitHubProvider) Capabilities() Capabilities {
+	return Capabilities{Releases: true, DefaultBranch: true, RequiresToken: true}
+}
+
+func (GitHubProvider) CloneURL(repoInfo RepoInfo) string {
+	return fmt.Sprintf("%s/%s/%s.git", gitHubURL(repoInfo), repoInfo.FullPath, repoInfo.RepoName)
+}
+
+// GitCredentials uses the token as the password of the x-access-token user
+func (GitHubProvider) GitCredentials(repoInfo RepoInfo) (string, string) {
+	return "x-access-token", repoInfo.Token
+}
+
+func (GitHubProvider) DefaultBranch(repoInfo RepoInfo) (string, error) {
+	client, err := newGitHubClient(repoInfo.Token, gitHubURL(repoInfo))
+	if err != nil {
+		return "", fmt.Errorf("failed to create GitHub client: %v", err)
+	}
+
+	repo, _, err := client.Repositories.Get(context.Background(), repoInfo.FullPath, repoInfo.RepoName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository: %v", err)
+	}
+	return repo.GetDefaultBranch(), nil
+}
+
+func (GitHubProvider) ListReleases(repoInfo RepoInfo) ([]Release, error) {
+	client, err := newGitHubClient(repoInfo.Token, gitHubURL(repoInfo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %v", err)
+	}
+
+	// Collect every page before deleting anything, so deletions cannot shift the pages
+	var result []Release
+	opts := &github.ListOptions{PerPage: gitHubPageSize}
+	for {
+		releases, resp, err := client.Repositories.ListReleases(context.Background(), repoInfo.FullPath, repoInfo.RepoName, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases (page %d): %v", max(opts.Page, 1), err)
+		}
+
+		for _, release := range releases {
+			result = append(result, Release{
+				ID:      release.GetID(),
+				Name:    release.GetName(),
+				TagName: release.GetTagName(),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			return result, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (GitHubProvider) DeleteRelease(repoInfo RepoInfo, release Release) error {
+	client, err := newGitHubClient(repoInfo.Token, gitHubURL(repoInfo))
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %v", err)
+	}
+
+	_, err = client.Repositories.DeleteRelease(context.Background(), repoInfo.FullPath, repoInfo.RepoName, release.ID)
+	return err
+}
+
+func (GitHubProvider) LatestRelease(repoInfo RepoInfo) (*Release, error) {
+	client, err := newGitHubClient(repoInfo.Token, gitHubURL(repoInfo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %v", err)
+	}
+
+	release, resp, err := client.Repositories.GetLatestRelease(context.Background(), repoInfo.FullPath, repoInfo.RepoName)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest release: %v", err)
+	}
+	return &Release{ID: release.GetID(), Name: release.GetName(), TagName: release.GetTagName()}, nil
+}
+
+func (GitHubProvider) SaveRelease(repoInfo RepoInfo, release Release, dir string) (SavedRelease, error) {
+	client, err := newGitHubClient(repoInfo.Token, gitHubURL(repoInfo))
+	if err != nil {
+		return SavedRelease{}, fmt.Errorf("failed to create GitHub client: %v", err)
+	}
+
+	ctx := context.Background()
+	full, _, err := client.Repositories.GetRelease(ctx, repoInfo.FullPath, repoInfo.RepoName, release.ID)
+	if err != nil {
+		return SavedRelease{}, fmt.Errorf("failed to get release: %v", err)
+	}
+
+	metadata, err := json.Marshal(full)
+	if err != nil {
+		return SavedRelease{}, fmt.Errorf("failed to encode release metadata: %v", err)
+	}
+
+	saved := SavedRelease{Release: release, Body: full.GetBody(), Prerelease: full.GetPrerelease(), Metadata: metadata}
+	for _, asset := range full.Assets {
+		rc, _, err := client.Repositories.DownloadReleaseAsset(ctx, repoInfo.FullPath, repoInfo.RepoName, asset.GetID(), restHTTPClient)
+		if err != nil {
+			return SavedRelease{}, fmt.Errorf("failed to download asset %s: %v", asset.GetName(), err)
+		}
+
+		path := filepath.Join(dir, filepath.Base(asset.GetName()))
+		err = writeFile(path, rc)
+		rc.Close()
+		if err != nil {
+			return SavedRelease{}, fmt.Errorf("failed to save asset %s: %v", asset.GetName(), err)
+		}
+		saved.Assets = append(saved.Assets, ReleaseAsset{Name: asset.GetName(), Path: path})
+	}
+	return saved, nil
+}
+
+func (GitHubProvider) RestoreRelease(repoInfo RepoInfo, saved SavedRelease) error {
+	client, err := newGitHubClient(repoInfo.Token, gitHubURL(repoInfo))
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %v", err)
+	}
+
+	ctx := context.Background()
+	created, _, err := client.Repositories.CreateRelease(ctx, repoInfo.FullPath, repoInfo.RepoName, &github.RepositoryRelease{
+		TagName:    github.String(saved.TagName),
+		Name:       github.String(saved.Name),
+		Body:       github.String(saved.Body),
+		Prerelease: github.Bool(saved.Prerelease),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create release: %v", err)
+	}
+
+	for _, asset := range saved.Assets {
+		file, err := os.Open(asset.Path)
+		if err != nil {
+			return fmt.Errorf("failed to open asset %s: %v", asset.Name, err)
+		}
+		_, _, err = client.Repositories.UploadReleaseAsset(ctx, repoInfo.FullPath, repoInfo.RepoName, created.GetID(), &github.UploadOptions{Name: asset.Name}, file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to upload asset %s: %v", asset.Name, err)
+		}
+	}
+	return nil
+}
+
+// DeleteGitHubReleases deletes every release of a GitHub repository
+func DeleteGitHubReleases(repoInfo RepoInfo) error {
+	return DeleteReleases(GitHubProvider{}, repoInfo)
+}
+
+// gitHubURL returns the GitHub instance URL, defaulting to github.com
+func gitHubURL(repoInfo RepoInfo) string {
+	if repoInfo.GitHubURL == "" {
+		return gitHubCloudURL
+	}
+	return strings.TrimSuffix(repoInfo.GitHubURL, "/")
+}
+
+func isGitHubCloud(baseURL string) bool {
+	parsed, err := url.Parse(baseURL)
+	return baseURL == "" || (err == nil && strings.EqualFold(parsed.Host, "github.com"))
+}
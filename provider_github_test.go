@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGitHubEnterpriseProvider(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo":
+			json.NewEncoder(w).Encode(map[string]string{"default_branch": "master"})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo/releases":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 1, "name": "First", "tag_name": "v1.0.0"},
+				{"id": 2, "name": "Second", "tag_name": "v2.0.0"},
+			})
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repoInfo := RepoInfo{
+		Provider:  GitHub,
+		FullPath:  "owner",
+		RepoName:  "repo",
+		Token:     "token",
+		GitHubURL: server.URL,
+	}
+
+	provider, err := GetProvider(GitHub)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cloneURL := provider.CloneURL(repoInfo); cloneURL != server.URL+"/owner/repo.git" {
+		t.Errorf("Expected clone URL %s, got %s", server.URL+"/owner/repo.git", cloneURL)
+	}
+
+	branch, err := provider.DefaultBranch(repoInfo)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if branch != "master" {
+		t.Errorf("Expected default branch master, got %s", branch)
+	}
+
+	if err := DeleteGitHubReleases(repoInfo); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(deleted) != 2 || deleted[0] != "/api/v3/repos/owner/repo/releases/1" || deleted[1] != "/api/v3/repos/owner/repo/releases/2" {
+		t.Errorf("Expected both releases to be deleted through the enterprise API, got %v", deleted)
+	}
+}
+
+func TestDeleteGitHubReleasesPaginated(t *testing.T) {
+	const total = 250
+
+	var server *httptest.Server
+	var deleted int
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+			if page == 0 {
+				page = 1
+			}
+			if perPage == 0 {
+				perPage = 30
+			}
+
+			var releases []map[string]interface{}
+			for id := (page-1)*perPage + 1; id <= page*perPage && id <= total; id++ {
+				releases = append(releases, map[string]interface{}{
+					"id":       id,
+					"name":     fmt.Sprintf("Release %d", id),
+					"tag_name": fmt.Sprintf("v%d", id),
+				})
+			}
+			if page*perPage < total {
+				w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d&per_page=%d>; rel="next"`, server.URL, r.URL.Path, page+1, perPage))
+			}
+			json.NewEncoder(w).Encode(releases)
+		case http.MethodDelete:
+			deleted++
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	repoInfo := RepoInfo{
+		Provider:  GitHub,
+		FullPath:  "owner",
+		RepoName:  "repo",
+		Token:     "token",
+		GitHubURL: server.URL,
+	}
+
+	output := captureOutput(func() {
+		if err := DeleteGitHubReleases(repoInfo); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	if deleted != total {
+		t.Errorf("Expected %d releases to be deleted across all pages, got %d", total, deleted)
+	}
+	if !strings.Contains(output, fmt.Sprintf("Found %d releases", total)) {
+		t.Errorf("Expected output to report %d releases, got %s", total, output)
+	}
+}
+
+// gitHTTPBackend serves the bare repository at /owner/repo.git over smart HTTP
+func gitHTTPBackend(t *testing.T, bare string) http.Handler {
+	t.Helper()
+
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "owner"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(bare, filepath.Join(root, "owner", "repo.git")); err != nil {
+		t.Fatal(err)
+	}
+	git(t, bare, "config", "http.receivepack", "true")
+
+	return &cgi.Handler{
+		Path: gitPath,
+		Args: []string{"http-backend"},
+		Root: "/",
+		Env:  []string{"GIT_PROJECT_ROOT=" + root, "GIT_HTTP_EXPORT_ALL=1"},
+	}
+}
+
+// fakeLatestRelease serves the releases endpoints used to keep the latest
+// release, with the repository itself served by backend
+type fakeLatestRelease struct {
+	backend      http.Handler
+	uploadStatus int
+	deleted      []string
+	created      map[string]interface{}
+	uploaded     string
+	uploadedName string
+}
+
+func (f *fakeLatestRelease) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/api/") {
+		f.backend.ServeHTTP(w, r)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo":
+		json.NewEncoder(w).Encode(map[string]string{"default_branch": "main"})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo/releases/latest":
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 2, "name": "Second", "tag_name": "v1.2"})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo/releases/2":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id": 2, "name": "Second", "tag_name": "v1.2", "body": "Release notes",
+			"assets": []map[string]interface{}{{"id": 7, "name": "app.tar.gz"}},
+		})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo/releases/assets/7":
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte("asset content"))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/owner/repo/releases":
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"id": 2, "name": "Second", "tag_name": "v1.2"},
+			{"id": 1, "name": "First", "tag_name": "v1.1"},
+		})
+	case r.Method == http.MethodDelete:
+		f.deleted = append(f.deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/owner/repo/releases":
+		json.NewDecoder(r.Body).Decode(&f.created)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 3, "tag_name": f.created["tag_name"]})
+	case r.Method == http.MethodPost && r.URL.Path == "/api/uploads/repos/owner/repo/releases/3/assets":
+		if f.uploadStatus != 0 {
+			w.WriteHeader(f.uploadStatus)
+			return
+		}
+		content, _ := io.ReadAll(r.Body)
+		f.uploaded, f.uploadedName = string(content), r.URL.Query().Get("name")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 8, "name": f.uploadedName})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestResetRepoKeepLatestRelease(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	fake := &fakeLatestRelease{backend: gitHTTPBackend(t, bare)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	repoInfo := RepoInfo{
+		Provider:          GitHub,
+		FullPath:          "owner",
+		RepoName:          "repo",
+		Token:             "token",
+		GitHubURL:         server.URL,
+		KeepLatestRelease: true,
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if dirs, _ := filepath.Glob(filepath.Join(os.TempDir(), "goresetit-release*")); len(dirs) != 0 {
+		t.Errorf("Expected the saved release to be removed once recreated, got %v", dirs)
+	}
+	if tags := git(t, bare, "tag"); tags != "v1.2" {
+		t.Errorf("Expected only the tag of the latest release to remain, got %s", tags)
+	}
+	if target, head := git(t, bare, "rev-parse", "v1.2^{commit}"), git(t, bare, "rev-parse", "main"); target != head {
+		t.Errorf("Expected v1.2 to point at the new root commit %s, got %s", head, target)
+	}
+	if len(fake.deleted) != 2 {
+		t.Errorf("Expected both releases to be deleted, got %v", fake.deleted)
+	}
+	if fake.created["tag_name"] != "v1.2" || fake.created["name"] != "Second" || fake.created["body"] != "Release notes" {
+		t.Errorf("Expected the latest release to be recreated with its notes, got %v", fake.created)
+	}
+	if fake.uploadedName != "app.tar.gz" || fake.uploaded != "asset content" {
+		t.Errorf("Expected asset app.tar.gz to be uploaded again, got %s with %q", fake.uploadedName, fake.uploaded)
+	}
+}
+
+func TestResetRepoKeepLatestReleaseRestoreFails(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	fake := &fakeLatestRelease{backend: gitHTTPBackend(t, bare), uploadStatus: http.StatusInternalServerError}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	repoInfo := RepoInfo{
+		Provider:          GitHub,
+		FullPath:          "owner",
+		RepoName:          "repo",
+		Token:             "token",
+		GitHubURL:         server.URL,
+		KeepLatestRelease: true,
+	}
+
+	// The release is saved under TMPDIR, outside the workspace
+	t.Setenv("TMPDIR", t.TempDir())
+
+	err := ResetRepo(repoInfo, "fresh start")
+	if err == nil {
+		t.Fatal("Expected the restore to fail")
+	}
+	dirs, _ := filepath.Glob(filepath.Join(os.TempDir(), "goresetit-release*"))
+	if len(dirs) != 1 {
+		t.Fatalf("Expected the saved release to be kept, got %v", dirs)
+	}
+	dir := dirs[0]
+	if !strings.Contains(err.Error(), "saved to "+dir) {
+		t.Errorf("Expected the error to name %s, got %v", dir, err)
+	}
+
+	if content, err := os.ReadFile(filepath.Join(dir, "app.tar.gz")); err != nil || string(content) != "asset content" {
+		t.Errorf("Expected asset app.tar.gz to be kept in %s, got %q (%v)", dir, content, err)
+	}
+	var saved SavedRelease
+	data, err := os.ReadFile(filepath.Join(dir, "release.json"))
+	if err != nil {
+		t.Fatalf("Expected release.json to be kept in %s: %v", dir, err)
+	}
+	if err := json.Unmarshal(data, &saved); err != nil || saved.TagName != "v1.2" || saved.Body != "Release notes" {
+		t.Errorf("Expected release.json to hold the latest release and its notes, got %s", data)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+const gitLabPageSize = 100
+
+// For mocking in tests
+var newGitLabClient = func(token, baseURL string) (*gitlab.Client, error) {
+	return gitlab.NewClient(token, gitlab.WithBaseURL(baseURL))
+}
+
+// GitLabProvider handles repositories hosted on gitlab.com or a private GitLab instance
+type GitLabProvider struct{}
+
+func init() {
+	RegisterProvider(GitLabProvider{})
+}
+
+func (GitLabProvider) Name() GitProvider {
+	return GitLab
+}
+
+func (GitLabProvider) Capabilities() Capabilities {
+	return Capabilities{Releases: true, DefaultBranch: true, RequiresToken: true}
+}
+
+func (GitLabProvider) CloneURL(repoInfo RepoInfo) string {
+	return fmt.Sprintf("%s/%s/%s.git", repoInfo.GitLabURL, repoInfo.FullPath, repoInfo.RepoName)
+}
+
+// GitCredentials uses the token as the password of the oauth2 user
+func (GitLabProvider) GitCredentials(repoInfo RepoInfo) (string, string) {
+	return "oauth2", repoInfo.Token
+}
+
+func (GitLabProvider) DefaultBranch(repoInfo RepoInfo) (string, error) {
+	client, err := newGitLabClient(repoInfo.Token, repoInfo.GitLabURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to create GitLab client: %v", err)
+	}
+
+	project, resp, err := client.Projects.GetProject(gitLabProjectPath(repoInfo), nil)
+	if err != nil {
+		return "", gitLabError("failed to get project", resp, err)
+	}
+	return project.DefaultBranch, nil
+}
+
+func (GitLabProvider) ListReleases(repoInfo RepoInfo) ([]Release, error) {
+	client, err := newGitLabClient(repoInfo.Token, repoInfo.GitLabURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitLab client: %v", err)
+	}
+
+	// Collect every page before deleting anything, so deletions cannot shift the pages
+	var result []Release
+	opts := &gitlab.ListReleasesOptions{ListOptions: gitlab.ListOptions{PerPage: gitLabPageSize}}
+	for {
+		releases, resp, err := client.Releases.ListReleases(gitLabProjectPath(repoInfo), opts)
+		if err != nil {
+			return nil, gitLabError(fmt.Sprintf("failed to list releases (page %d)", max(opts.Page, 1)), resp, err)
+		}
+
+		for _, release := range releases {
+			result = append(result, Release{
+				Name:    release.Name,
+				TagName: release.TagName,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			return result, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (GitLabProvider) DeleteRelease(repoInfo RepoInfo, release Release) error {
+	client, err := newGitLabClient(repoInfo.Token, repoInfo.GitLabURL)
+	if err != nil {
+		return fmt.Errorf("failed to create GitLab client: %v", err)
+	}
+
+	_, resp, err := client.Releases.DeleteRelease(gitLabProjectPath(repoInfo), release.TagName)
+	if err != nil {
+		return gitLabError("failed to delete release", resp, err)
+	}
+	return nil
+}
+
+func (GitLabProvider) LatestRelease(repoInfo RepoInfo) (*Release, error) {
+	client, err := newGitLabClient(repoInfo.Token, repoInfo.GitLabURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitLab client: %v", err)
+	}
+
+	// Releases are listed by release date, most recent first
+	releases, resp, err := client.Releases.ListReleases(gitLabProjectPath(repoInfo), &gitlab.ListReleasesOptions{ListOptions: gitlab.ListOptions{PerPage: 1}})
+	if err != nil {
+		return nil, gitLabError("failed to list releases", resp, err)
+	}
+	if len(releases) == 0 {
+		return nil, nil
+	}
+	return &Release{Name: releases[0].Name, TagName: releases[0].TagName}, nil
+}
+
+// SaveRelease downloads the targets of the release asset links, which are
+// recreated as links. The token is only sent to the GitLab instance itself.
+func (GitLabProvider) SaveRelease(repoInfo RepoInfo, release Release, dir string) (SavedRelease, error) {
+	client, err := newGitLabClient(repoInfo.Token, repoInfo.GitLabURL)
+	if err != nil {
+		return SavedRelease{}, fmt.Errorf("failed to create GitLab client: %v", err)
+	}
+
+	full, resp, err := client.Releases.GetRelease(gitLabProjectPath(repoInfo), release.TagName)
+	if err != nil {
+		return SavedRelease{}, gitLabError("failed to get release", resp, err)
+	}
+
+	metadata, err := json.Marshal(full)
+	if err != nil {
+		return SavedRelease{}, fmt.Errorf("failed to encode release metadata: %v", err)
+	}
+
+	saved := SavedRelease{Release: release, Body: full.Description, Metadata: metadata}
+	for _, link := range full.Assets.Links {
+		header := http.Header{}
+		if strings.HasPrefix(link.URL, strings.TrimSuffix(repoInfo.GitLabURL, "/")+"/") {
+			header.Set("PRIVATE-TOKEN", repoInfo.Token)
+		}
+
+		path := filepath.Join(dir, filepath.Base(link.Name))
+		if err := DownloadFile(link.URL, header, path); err != nil {
+			return SavedRelease{}, fmt.Errorf("failed to download asset %s: %v", link.Name, err)
+		}
+		saved.Assets = append(saved.Assets, ReleaseAsset{Name: link.Name, Path: path, URL: link.URL, LinkType: string(link.LinkType)})
+	}
+	return saved, nil
+}
+
+func (GitLabProvider) RestoreRelease(repoInfo RepoInfo, saved SavedRelease) error {
+	client, err := newGitLabClient(repoInfo.Token, repoInfo.GitLabURL)
+	if err != nil {
+		return fmt.Errorf("failed to create GitLab client: %v", err)
+	}
+
+	opts := &gitlab.CreateReleaseOptions{
+		Name:        gitlab.String(saved.Name),
+		TagName:     gitlab.String(saved.TagName),
+		Description: gitlab.String(saved.Body),
+	}
+	if len(saved.Assets) > 0 {
+		opts.Assets = &gitlab.ReleaseAssetsOptions{}
+		for _, asset := range saved.Assets {
+			link := &gitlab.ReleaseAssetLinkOptions{Name: gitlab.String(asset.Name), URL: gitlab.String(asset.URL)}
+			if asset.LinkType != "" {
+				linkType := gitlab.LinkTypeValue(asset.LinkType)
+				link.LinkType = &linkType
+			}
+			opts.Assets.Links = append(opts.Assets.Links, link)
+		}
+	}
+
+	_, resp, err := client.Releases.CreateRelease(gitLabProjectPath(repoInfo), opts)
+	if err != nil {
+		return gitLabError("failed to create release", resp, err)
+	}
+	return nil
+}
+
+// DeleteGitLabReleases deletes every release of a GitLab project
+func DeleteGitLabReleases(repoInfo RepoInfo) error {
+	return DeleteReleases(GitLabProvider{}, repoInfo)
+}
+
+func gitLabProjectPath(repoInfo RepoInfo) string {
+	return repoInfo.FullPath + "/" + repoInfo.RepoName
+}
+
+// gitLabError adds the HTTP status code to an API error when one is available
+func gitLabError(msg string, resp *gitlab.Response, err error) error {
+	if resp != nil {
+		return fmt.Errorf("%s (status %d): %v", msg, resp.StatusCode, err)
+	}
+	return fmt.Errorf("%s: %v", msg, err)
+}
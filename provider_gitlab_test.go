@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDeleteGitLabReleasesPaginated(t *testing.T) {
+	const total = 230
+
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/releases"):
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+			if page == 0 {
+				page = 1
+			}
+			if perPage == 0 {
+				perPage = 20
+			}
+
+			var releases []map[string]interface{}
+			for i := (page-1)*perPage + 1; i <= page*perPage && i <= total; i++ {
+				releases = append(releases, map[string]interface{}{
+					"name":     fmt.Sprintf("Release %d", i),
+					"tag_name": fmt.Sprintf("v%d", i),
+				})
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Page", strconv.Itoa(page))
+			w.Header().Set("X-Per-Page", strconv.Itoa(perPage))
+			w.Header().Set("X-Total", strconv.Itoa(total))
+			w.Header().Set("X-Total-Pages", strconv.Itoa((total+perPage-1)/perPage))
+			if page*perPage < total {
+				w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+			}
+			json.NewEncoder(w).Encode(releases)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte("{}"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repoInfo := RepoInfo{
+		Provider:  GitLab,
+		FullPath:  "group",
+		RepoName:  "repo",
+		Token:     "token",
+		GitLabURL: server.URL,
+	}
+
+	output := captureOutput(func() {
+		if err := DeleteGitLabReleases(repoInfo); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	if len(deleted) != total {
+		t.Errorf("Expected %d releases to be deleted across all pages, got %d", total, len(deleted))
+	}
+	if !strings.Contains(output, fmt.Sprintf("Deleted all %d releases", total)) {
+		t.Errorf("Expected output to report %d deleted releases, got %s", total, output)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGetProvider(t *testing.T) {
+	testCases := []struct {
+		name        string
+		provider    GitProvider
+		expected    GitProvider
+		expectError bool
+	}{
+		{
+			name:     "GitHub",
+			provider: "github",
+			expected: GitHub,
+		},
+		{
+			name:     "GitLab is case insensitive",
+			provider: "GitLab",
+			expected: GitLab,
+		},
+		{
+			name:        "Unknown provider",
+			provider:    "invalid",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := GetProvider(tc.provider)
+
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if provider.Name() != tc.expected {
+				t.Errorf("Expected provider %s, got %s", tc.expected, provider.Name())
+			}
+		})
+	}
+}
+
+func TestProviderCloneURL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		repoInfo RepoInfo
+		expected string
+	}{
+		{
+			name: "GitHub",
+			repoInfo: RepoInfo{
+				Provider: GitHub,
+				FullPath: "owner",
+				RepoName: "repo",
+			},
+			expected: "https://github.com/owner/repo.git",
+		},
+		{
+			name: "GitLab with subgroup",
+			repoInfo: RepoInfo{
+				Provider:  GitLab,
+				FullPath:  "group/subgroup",
+				RepoName:  "repo",
+				GitLabURL: "https://gitlab.company.com",
+			},
+			expected: "https://gitlab.company.com/group/subgroup/repo.git",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := GetProvider(tc.repoInfo.Provider)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			cloneURL := provider.CloneURL(tc.repoInfo)
+			if cloneURL != tc.expected {
+				t.Errorf("Expected clone URL %s, got %s", tc.expected, cloneURL)
+			}
+		})
+	}
+}
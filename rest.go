@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// RESTClient is a minimal JSON client for provider APIs that have no Go SDK
+type RESTClient struct {
+	BaseURL string
+	Header  http.Header
+}
+
+// For mocking in tests
+var restHTTPClient = http.DefaultClient
+
+// APIError is returned when a provider API answers with a non-2xx status
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("%s %s failed (status %d): %s", e.Method, e.URL, e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("%s %s failed (status %d)", e.Method, e.URL, e.StatusCode)
+}
+
+// Do sends a request to path (relative to BaseURL), encoding body and decoding
+// the response into out when they are not nil
+func (c RESTClient) Do(method, path string, body, out interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	url := strings.TrimSuffix(c.BaseURL, "/") + path
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := restHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return resp, &APIError{
+			Method:     method,
+			URL:        url,
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(data)),
+		}
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp, fmt.Errorf("failed to decode response from %s: %v", url, err)
+		}
+	}
+	return resp, nil
+}
+
+// DownloadFile saves the content served at url to path, sending header with the request
+func DownloadFile(url string, header http.Header, path string) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := restHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &APIError{Method: http.MethodGet, URL: url, StatusCode: resp.StatusCode}
+	}
+	return writeFile(path, resp.Body)
+}
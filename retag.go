@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RetagLatest selects the highest semantic version tag in --retag
+const RetagLatest = "latest"
+
+var semverPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// RetagSelector picks the tags --retag recreates on the new root commit
+type RetagSelector struct {
+	latest   bool
+	patterns []func(string) bool
+}
+
+// TagSnapshot holds what is needed to recreate a tag on another commit
+type TagSnapshot struct {
+	Name        string
+	Annotated   bool
+	Signed      bool
+	Message     string
+	TaggerName  string
+	TaggerEmail string
+	TaggerDate  string
+}
+
+// NewRetagSelector compiles a --retag specification: "latest" for the highest
+// semantic version tag, and any number of tag globs or /regexes/
+func NewRetagSelector(spec []string) (*RetagSelector, error) {
+	selector := &RetagSelector{}
+	for _, pattern := range spec {
+		if pattern == RetagLatest {
+			selector.latest = true
+			continue
+		}
+		match, err := compileTagPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		selector.patterns = append(selector.patterns, match)
+	}
+	return selector, nil
+}
+
+// Select returns the tags to recreate, in the order they appear in tags
+func (s *RetagSelector) Select(tags []string) []string {
+	latest := ""
+	if s.latest {
+		for _, tag := range tags {
+			if semverPattern.MatchString(tag) && (latest == "" || compareSemver(tag, latest) > 0) {
+				latest = tag
+			}
+		}
+	}
+
+	var selected []string
+	for _, tag := range tags {
+		if tag == latest || matchAny(s.patterns, tag) {
+			selected = append(selected, tag)
+		}
+	}
+	return selected
+}
+
+// ReadTag snapshots a local tag, including the message and tagger of annotated tags
+func ReadTag(name string) (TagSnapshot, error) {
+	tag := TagSnapshot{Name: name}
+	ref := fmt.Sprintf("refs/tags/%s", name)
+
+	objectType, err := GitCommandOutput("cat-file", "-t", ref)
+	if err != nil {
+		return tag, err
+	}
+	if objectType != "tag" {
+		return tag, nil
+	}
+
+	raw, err := GitCommandOutput("cat-file", "tag", ref)
+	if err != nil {
+		return tag, err
+	}
+	tag.Annotated = true
+
+	header, message, _ := strings.Cut(raw, "\n\n")
+	for _, line := range strings.Split(header, "\n") {
+		tagger, ok := strings.CutPrefix(line, "tagger ")
+		if !ok {
+			continue
+		}
+		// tagger Name <email> 1700000000 +0000
+		name, rest, _ := strings.Cut(tagger, " <")
+		email, date, _ := strings.Cut(rest, "> ")
+		tag.TaggerName, tag.TaggerEmail, tag.TaggerDate = name, email, date
+	}
+
+	// Signatures are appended to the message and cannot survive the retag
+	for _, marker := range []string{"-----BEGIN PGP SIGNATURE-----", "-----BEGIN SSH SIGNATURE-----", "-----BEGIN SIGNED MESSAGE-----"} {
+		if i := strings.Index(message, marker); i >= 0 {
+			message = message[:i]
+			tag.Signed = true
+		}
+	}
+	tag.Message = strings.TrimRight(message, "\n") + "\n"
+
+	return tag, nil
+}
+
+// RecreateTag creates tag on target, keeping the message and tagger of
+// annotated tags. Signed tags are re-signed when a signing key is configured.
+func RecreateTag(tag TagSnapshot, target string) error {
+	if !tag.Annotated {
+		return RunGitCommandWithOutput("tag", "-f", tag.Name, target)
+	}
+
+	args := []string{"tag", "-f", "-a", "--cleanup=verbatim", "-m", tag.Message}
+	if tag.Signed {
+		if signingConfigured() {
+			args = append(args, "-s")
+		} else {
+			fmt.Println(warning.Render(fmt.Sprintf("Warning: Tag %s was signed but no signing key is configured, recreating it unsigned", tag.Name)))
+		}
+	}
+	args = append(args, tag.Name, target)
+
+	// git records the committer identity as the tagger
+	var env []string
+	if tag.TaggerName != "" {
+		env = []string{
+			"GIT_COMMITTER_NAME=" + tag.TaggerName,
+			"GIT_COMMITTER_EMAIL=" + tag.TaggerEmail,
+			"GIT_COMMITTER_DATE=" + tag.TaggerDate,
+		}
+	}
+	return execGitCommand(env, args...)
+}
+
+// signingConfigured reports whether git is set up to sign tags
+func signingConfigured() bool {
+	if sign, _ := GitCommandOutput("config", "--type=bool", "tag.gpgSign"); sign == "true" {
+		return true
+	}
+	key, _ := GitCommandOutput("config", "user.signingKey")
+	return key != ""
+}
+
+// compareSemver compares two semantic version tags, following the semver
+// precedence rules where a pre-release sorts before its release
+func compareSemver(a, b string) int {
+	ma, mb := semverPattern.FindStringSubmatch(a), semverPattern.FindStringSubmatch(b)
+	for i := 1; i <= 3; i++ {
+		if c := compareNumeric(ma[i], mb[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case ma[4] == mb[4]:
+		return 0
+	case ma[4] == "":
+		return 1
+	case mb[4] == "":
+		return -1
+	}
+
+	pa, pb := strings.Split(ma[4], "."), strings.Split(mb[4], ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		_, errA := strconv.ParseUint(pa[i], 10, 64)
+		_, errB := strconv.ParseUint(pb[i], 10, 64)
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = compareNumeric(pa[i], pb[i])
+		case errA == nil:
+			c = -1
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(pa[i], pb[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return len(pa) - len(pb)
+}
+
+// compareNumeric compares two decimal strings of arbitrary length
+func compareNumeric(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
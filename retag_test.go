@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRetagSelector(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     string
+		tags     []string
+		expected []string
+	}{
+		{
+			name:     "Latest semver",
+			spec:     "latest",
+			tags:     []string{"v1.9.0", "v1.10.0", "v1.2.3", "nightly"},
+			expected: []string{"v1.10.0"},
+		},
+		{
+			name:     "Release beats pre-release",
+			spec:     "latest",
+			tags:     []string{"v2.0.0-rc.2", "v2.0.0", "v2.0.0-rc.10"},
+			expected: []string{"v2.0.0"},
+		},
+		{
+			name:     "Pre-release ordering",
+			spec:     "latest",
+			tags:     []string{"v2.0.0-alpha", "v2.0.0-rc.2", "v2.0.0-rc.10", "v1.0.0"},
+			expected: []string{"v2.0.0-rc.10"},
+		},
+		{
+			name:     "No semver tags",
+			spec:     "latest",
+			tags:     []string{"nightly", "stable"},
+			expected: nil,
+		},
+		{
+			name:     "Latest and pattern",
+			spec:     "latest,stable",
+			tags:     []string{"stable", "v1.0.0", "v0.9.0"},
+			expected: []string{"stable", "v1.0.0"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			selector, err := NewRetagSelector(ParseTagPatterns(tc.spec))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			selected := selector.Select(tc.tags)
+			if strings.Join(selected, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("Expected tags %v, got %v", tc.expected, selected)
+			}
+		})
+	}
+}
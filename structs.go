@@ -1,30 +1,129 @@
 package main
 
-// GitProvider represents the supported Git hosting providers
-type GitProvider int
+import (
+	"encoding/json"
+	"time"
+)
+
+// GitProvider is the registry name of a supported Git hosting provider
+type GitProvider string
+
+const (
+	GitHub    GitProvider = "github"
+	GitLab    GitProvider = "gitlab"
+	Gitea     GitProvider = "gitea"
+	Bitbucket GitProvider = "bitbucket"
+	Azure     GitProvider = "azure"
+	GitRemote GitProvider = "git"
+)
+
+// Transport selects how git talks to the remote
+type Transport string
+
+const (
+	TransportHTTPS Transport = "https"
+	TransportSSH   Transport = "ssh"
+)
+
+// CloneStrategy selects how much of the repository is cloned for the reset
+type CloneStrategy string
 
 const (
-	GitHub GitProvider = iota
-	GitLab
+	CloneBlobless CloneStrategy = "blobless"
+	CloneShallow  CloneStrategy = "shallow"
+	CloneFull     CloneStrategy = "full"
 )
 
 // RepoInfo contains all repository-related information
 type RepoInfo struct {
-	Provider  GitProvider
-	FullPath  string
-	RepoName  string
-	Token     string
-	GitLabURL string
-	DryRun    bool
+	Provider          GitProvider
+	FullPath          string
+	RepoName          string
+	Token             string
+	GitHubURL         string
+	GitLabURL         string
+	GiteaURL          string
+	BitbucketURL      string
+	AzureURL          string
+	Transport         Transport
+	SSHKey            string
+	KnownHosts        string
+	Branch            string
+	CloneStrategy     CloneStrategy
+	From              string
+	KeepLast          int
+	SquashBefore      time.Time
+	KeepTags          []string
+	DeleteTags        []string
+	Retag             []string
+	KeepLatestRelease bool
+	ArchiveDir        string
+	Backup            string
+	BackupRemote      string
+	DryRun            bool
+}
+
+// Release is the provider-agnostic view of a hosted release
+type Release struct {
+	ID      int64  `json:"id,omitempty"`
+	Name    string `json:"name"`
+	TagName string `json:"tag_name"`
+}
+
+// SavedRelease is a release saved before the reset, with its notes, assets
+// and the raw metadata returned by the provider API
+type SavedRelease struct {
+	Release
+	Body       string          `json:"body"`
+	Prerelease bool            `json:"prerelease"`
+	Assets     []ReleaseAsset  `json:"assets"`
+	Metadata   json.RawMessage `json:"metadata,omitempty"`
+}
+
+// ReleaseAsset is a release file downloaded to Path. Providers whose assets
+// are links also record the URL they are recreated with.
+type ReleaseAsset struct {
+	Name     string `json:"name"`
+	Path     string `json:"path,omitempty"`
+	URL      string `json:"url,omitempty"`
+	LinkType string `json:"link_type,omitempty"`
+}
+
+// Capabilities describes the optional features a provider supports
+type Capabilities struct {
+	Releases      bool
+	DefaultBranch bool
+	RequiresToken bool
 }
 
 // CommandLineFlags holds all possible command line arguments
 type CommandLineFlags struct {
-	RepoPath      string
-	Token         string
-	Provider      string
-	GitLabURL     string
-	DryRun        bool
-	NoInteractive bool
-	CommitMsg     string
+	RepoPath          string
+	Token             string
+	TokenFile         string
+	Provider          string
+	GitHubURL         string
+	GitLabURL         string
+	GiteaURL          string
+	BitbucketURL      string
+	AzureURL          string
+	Transport         string
+	SSHKey            string
+	KnownHosts        string
+	Branch            string
+	CloneStrategy     string
+	From              string
+	KeepLast          int
+	SquashBefore      string
+	KeepTags          string
+	DeleteTags        string
+	Retag             string
+	KeepLatestRelease bool
+	ArchiveDir        string
+	Backup            string
+	BackupRemote      string
+	Bundle            string
+	DryRun            bool
+	NoInteractive     bool
+	CommitMsg         string
 }
\ No newline at end of file
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// TagFilter decides which tags are deleted from the --keep-tags and
+// --delete-tags patterns
+type TagFilter struct {
+	keep   []func(string) bool
+	delete []func(string) bool
+}
+
+// NewTagFilter compiles keep and delete patterns. A pattern wrapped in slashes
+// is a regular expression, anything else is a glob.
+func NewTagFilter(keep, delete []string) (*TagFilter, error) {
+	filter := &TagFilter{}
+	for _, pattern := range keep {
+		match, err := compileTagPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		filter.keep = append(filter.keep, match)
+	}
+	for _, pattern := range delete {
+		match, err := compileTagPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		filter.delete = append(filter.delete, match)
+	}
+	return filter, nil
+}
+
+// Split separates the tags to delete from the tags to keep. Without delete
+// patterns every tag is a candidate, and keep patterns always win.
+func (f *TagFilter) Split(tags []string) (deleted, kept []string) {
+	for _, tag := range tags {
+		if matchAny(f.keep, tag) || (len(f.delete) > 0 && !matchAny(f.delete, tag)) {
+			kept = append(kept, tag)
+		} else {
+			deleted = append(deleted, tag)
+		}
+	}
+	return deleted, kept
+}
+
+// ParseTagPatterns splits a comma-separated list of tag patterns
+func ParseTagPatterns(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+func compileTagPattern(pattern string) (func(string) bool, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag regex %s: %v", pattern, err)
+		}
+		return re.MatchString, nil
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid tag glob %s: %v", pattern, err)
+	}
+	return func(tag string) bool {
+		matched, _ := path.Match(pattern, tag)
+		return matched
+	}, nil
+}
+
+func matchAny(matchers []func(string) bool, tag string) bool {
+	for _, match := range matchers {
+		if match(tag) {
+			return true
+		}
+	}
+	return false
+}
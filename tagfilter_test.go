@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTagFilter(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.1.0", "v2.0.0-rc.1", "v2.0.0", "release/2024"}
+
+	testCases := []struct {
+		name            string
+		keep            string
+		delete          string
+		expectedDeleted []string
+		expectedKept    []string
+		expectError     bool
+	}{
+		{
+			name:            "No filters",
+			expectedDeleted: tags,
+		},
+		{
+			name:            "Keep glob",
+			keep:            "v1.*",
+			expectedDeleted: []string{"v2.0.0-rc.1", "v2.0.0", "release/2024"},
+			expectedKept:    []string{"v1.0.0", "v1.1.0"},
+		},
+		{
+			name:            "Delete regex",
+			delete:          "/-rc\\.[0-9]+$/",
+			expectedDeleted: []string{"v2.0.0-rc.1"},
+			expectedKept:    []string{"v1.0.0", "v1.1.0", "v2.0.0", "release/2024"},
+		},
+		{
+			name:            "Keep wins over delete",
+			keep:            "v1.1.0",
+			delete:          "v1.*, release/*",
+			expectedDeleted: []string{"v1.0.0", "release/2024"},
+			expectedKept:    []string{"v1.1.0", "v2.0.0-rc.1", "v2.0.0"},
+		},
+		{
+			name:        "Invalid regex",
+			delete:      "/v(/",
+			expectError: true,
+		},
+		{
+			name:        "Invalid glob",
+			keep:        "v[1",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := NewTagFilter(ParseTagPatterns(tc.keep), ParseTagPatterns(tc.delete))
+
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			deleted, kept := filter.Split(tags)
+			if strings.Join(deleted, ",") != strings.Join(tc.expectedDeleted, ",") {
+				t.Errorf("Expected deleted tags %v, got %v", tc.expectedDeleted, deleted)
+			}
+			if strings.Join(kept, ",") != strings.Join(tc.expectedKept, ",") {
+				t.Errorf("Expected kept tags %v, got %v", tc.expectedKept, kept)
+			}
+		})
+	}
+}
@@ -241,13 +241,13 @@ func TestDeleteGitHubReleases(t *testing.T) {
 			oldNewGitHubClient := main.NewGitHubClient
 			defer func() { main.NewGitHubClient = oldNewGitHubClient }()
 
-			main.NewGitHubClient = func(token string) *github.Client {
+			main.NewGitHubClient = func(token, baseURL string) (*github.Client, error) {
 				return &github.Client{
 					Repositories: &mockGitHubClient{
 						releases: tc.releases,
 						err:      tc.listErr,
 					},
-				}
+				}, nil
 			}
 
 			err := main.DeleteGitHubReleases(tc.repoInfo)
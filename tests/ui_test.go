@@ -203,7 +203,7 @@ func TestPromptConfirmation(t *testing.T) {
                 }
             }
 
-            result, err := main.PromptConfirmation(tc.dryRun)
+            result, err := main.PromptConfirmation(tc.dryRun, "")
 
             if tc.expectError {
                 if err == nil {
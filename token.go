@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const tokenEnvVar = "GORESETIT_TOKEN"
+
+// ResolveToken looks for the access token in, by order of precedence: --token,
+// --token-file, the <PROVIDER>_TOKEN and GORESETIT_TOKEN environment variables,
+// and finally git credential fill for the provider host. It returns the token
+// and a description of its source, which is safe to log. An empty token with
+// no error means none was found.
+func ResolveToken(provider Provider, repoInfo RepoInfo, flags CommandLineFlags) (string, string, error) {
+	if flags.Token != "" {
+		return flags.Token, "--token flag", nil
+	}
+
+	if flags.TokenFile != "" {
+		data, err := os.ReadFile(flags.TokenFile)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read token file: %v", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", "", fmt.Errorf("token file %s is empty", flags.TokenFile)
+		}
+		return token, fmt.Sprintf("token file %s", flags.TokenFile), nil
+	}
+
+	for _, name := range []string{strings.ToUpper(string(provider.Name())) + "_TOKEN", tokenEnvVar} {
+		if token := strings.TrimSpace(os.Getenv(name)); token != "" {
+			return token, fmt.Sprintf("%s environment variable", name), nil
+		}
+	}
+
+	if provider.Capabilities().RequiresToken {
+		if token := gitCredentialFill(provider.CloneURL(repoInfo)); token != "" {
+			return token, "git credential helper", nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// gitCredentialFill asks the configured git credential helpers for the
+// password of an HTTPS remote, without ever prompting the user
+func gitCredentialFill(remoteURL string) string {
+	parsed, err := url.Parse(remoteURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return ""
+	}
+
+	input := fmt.Sprintf("protocol=%s\nhost=%s\npath=%s\n\n", parsed.Scheme, parsed.Host, strings.TrimPrefix(parsed.Path, "/"))
+
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=", "GCM_INTERACTIVE=never")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		if password, ok := strings.CutPrefix(scanner.Text(), "password="); ok {
+			return password
+		}
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveToken(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name           string
+		flags          CommandLineFlags
+		env            map[string]string
+		expectedToken  string
+		expectedSource string
+		expectError    bool
+	}{
+		{
+			name:           "Flag takes precedence",
+			flags:          CommandLineFlags{Token: "flag-token", TokenFile: tokenFile},
+			env:            map[string]string{"GITHUB_TOKEN": "env-token"},
+			expectedToken:  "flag-token",
+			expectedSource: "--token flag",
+		},
+		{
+			name:           "Token file before environment",
+			flags:          CommandLineFlags{TokenFile: tokenFile},
+			env:            map[string]string{"GITHUB_TOKEN": "env-token"},
+			expectedToken:  "file-token",
+			expectedSource: "token file",
+		},
+		{
+			name:           "Provider environment variable",
+			env:            map[string]string{"GITHUB_TOKEN": "github-token", "GORESETIT_TOKEN": "generic-token"},
+			expectedToken:  "github-token",
+			expectedSource: "GITHUB_TOKEN",
+		},
+		{
+			name:           "Generic environment variable",
+			env:            map[string]string{"GORESETIT_TOKEN": "generic-token"},
+			expectedToken:  "generic-token",
+			expectedSource: "GORESETIT_TOKEN",
+		},
+		{
+			name: "Git credential helper",
+			env: map[string]string{
+				"GIT_CONFIG_COUNT":   "1",
+				"GIT_CONFIG_KEY_0":   "credential.helper",
+				"GIT_CONFIG_VALUE_0": "!f() { echo username=user; echo password=helper-token; }; f",
+			},
+			expectedToken:  "helper-token",
+			expectedSource: "git credential helper",
+		},
+		{
+			name:        "Missing token file",
+			flags:       CommandLineFlags{TokenFile: filepath.Join(t.TempDir(), "missing")},
+			expectError: true,
+		},
+	}
+
+	provider, err := GetProvider(GitHub)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repoInfo := RepoInfo{Provider: GitHub, FullPath: "owner", RepoName: "repo"}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", "")
+			t.Setenv("GORESETIT_TOKEN", "")
+			t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+			t.Setenv("HOME", t.TempDir())
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			token, source, err := ResolveToken(provider, repoInfo, tc.flags)
+
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if token != tc.expectedToken {
+				t.Errorf("Expected token %s, got %s", tc.expectedToken, token)
+			}
+			if !strings.Contains(source, tc.expectedSource) {
+				t.Errorf("Expected source to mention %s, got %s", tc.expectedSource, source)
+			}
+			if strings.Contains(source, token) {
+				t.Errorf("Token leaked into source description %s", source)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// SSHRemote is implemented by providers whose SSH URLs cannot be derived from
+// the HTTPS clone URL as git@host:path
+type SSHRemote interface {
+	SSHCloneURL(repoInfo RepoInfo) string
+}
+
+// RemoteURL returns the URL to clone from and push to for the configured transport
+func RemoteURL(provider Provider, repoInfo RepoInfo) (string, error) {
+	switch repoInfo.Transport {
+	case "", TransportHTTPS:
+		return provider.CloneURL(repoInfo), nil
+	case TransportSSH:
+		if remote, ok := provider.(SSHRemote); ok {
+			return remote.SSHCloneURL(repoInfo), nil
+		}
+		return sshURLFromHTTPS(provider.CloneURL(repoInfo))
+	default:
+		return "", fmt.Errorf("unsupported transport '%s' (use https or ssh)", repoInfo.Transport)
+	}
+}
+
+// NewGitAuth returns the credentials git uses for the configured transport:
+// the token over HTTPS, or the SSH key and known hosts file over SSH
+func NewGitAuth(provider Provider, repoInfo RepoInfo) GitAuth {
+	if repoInfo.Transport == TransportSSH {
+		return GitAuth{SSHCommand: sshCommand(repoInfo)}
+	}
+
+	username, password := provider.GitCredentials(repoInfo)
+	return GitAuth{Username: username, Token: password}
+}
+
+// sshURLFromHTTPS turns https://host/path.git into git@host:path.git
+func sshURLFromHTTPS(cloneURL string) (string, error) {
+	parsed, err := url.Parse(cloneURL)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("cannot derive an SSH URL from '%s'", cloneURL)
+	}
+	return fmt.Sprintf("git@%s:%s", parsed.Hostname(), strings.TrimPrefix(parsed.Path, "/")), nil
+}
+
+// resolveSSHPaths makes the SSH key and known hosts paths absolute, as ssh
+// runs from the temporary directory git commands are run in
+func resolveSSHPaths(repoInfo *RepoInfo) error {
+	var err error
+	if repoInfo.SSHKey != "" {
+		if repoInfo.SSHKey, err = filepath.Abs(repoInfo.SSHKey); err != nil {
+			return fmt.Errorf("failed to resolve SSH key path: %v", err)
+		}
+	}
+	if repoInfo.KnownHosts != "" {
+		if repoInfo.KnownHosts, err = filepath.Abs(repoInfo.KnownHosts); err != nil {
+			return fmt.Errorf("failed to resolve known hosts path: %v", err)
+		}
+	}
+	return nil
+}
+
+// sshCommand builds GIT_SSH_COMMAND, or returns an empty string to leave the
+// user's ssh configuration untouched
+func sshCommand(repoInfo RepoInfo) string {
+	if repoInfo.SSHKey == "" && repoInfo.KnownHosts == "" {
+		return ""
+	}
+
+	command := []string{"ssh"}
+	if repoInfo.SSHKey != "" {
+		command = append(command, "-i", shellQuote(repoInfo.SSHKey), "-o", "IdentitiesOnly=yes")
+	}
+	if repoInfo.KnownHosts != "" {
+		command = append(command, "-o", "UserKnownHostsFile="+shellQuote(repoInfo.KnownHosts), "-o", "StrictHostKeyChecking=yes")
+	}
+	return strings.Join(command, " ")
+}
+
+// shellQuote quotes a value for the shell git runs GIT_SSH_COMMAND with
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
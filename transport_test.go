@@ -0,0 +1,181 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRemoteURL(t *testing.T) {
+	testCases := []struct {
+		name        string
+		repoInfo    RepoInfo
+		expected    string
+		expectError bool
+	}{
+		{
+			name: "GitHub over HTTPS by default",
+			repoInfo: RepoInfo{
+				Provider: GitHub,
+				FullPath: "owner",
+				RepoName: "repo",
+			},
+			expected: "https://github.com/owner/repo.git",
+		},
+		{
+			name: "GitHub over SSH",
+			repoInfo: RepoInfo{
+				Provider:  GitHub,
+				FullPath:  "owner",
+				RepoName:  "repo",
+				Transport: TransportSSH,
+			},
+			expected: "git@github.com:owner/repo.git",
+		},
+		{
+			name: "Self-hosted GitLab over SSH",
+			repoInfo: RepoInfo{
+				Provider:  GitLab,
+				FullPath:  "group/subgroup",
+				RepoName:  "repo",
+				GitLabURL: "https://gitlab.company.com",
+				Transport: TransportSSH,
+			},
+			expected: "git@gitlab.company.com:group/subgroup/repo.git",
+		},
+		{
+			name: "Bitbucket Data Center over SSH",
+			repoInfo: RepoInfo{
+				Provider:     Bitbucket,
+				FullPath:     "PROJ",
+				RepoName:     "repo",
+				BitbucketURL: "https://bitbucket.company.com",
+				Transport:    TransportSSH,
+			},
+			expected: "ssh://git@bitbucket.company.com:7999/proj/repo.git",
+		},
+		{
+			name: "Azure DevOps over SSH",
+			repoInfo: RepoInfo{
+				Provider:  Azure,
+				FullPath:  "org/project",
+				RepoName:  "repo",
+				AzureURL:  "https://dev.azure.com",
+				Transport: TransportSSH,
+			},
+			expected: "git@ssh.dev.azure.com:v3/org/project/repo",
+		},
+		{
+			name: "Unknown transport",
+			repoInfo: RepoInfo{
+				Provider:  GitHub,
+				FullPath:  "owner",
+				RepoName:  "repo",
+				Transport: "ftp",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := GetProvider(tc.repoInfo.Provider)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			remoteURL, err := RemoteURL(provider, tc.repoInfo)
+
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if remoteURL != tc.expected {
+				t.Errorf("Expected remote URL %s, got %s", tc.expected, remoteURL)
+			}
+		})
+	}
+}
+
+func TestNewGitAuthSSH(t *testing.T) {
+	provider, err := GetProvider(GitHub)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	auth := NewGitAuth(provider, RepoInfo{
+		Token:      "token",
+		Transport:  TransportSSH,
+		SSHKey:     "/home/user/.ssh/deploy key",
+		KnownHosts: "/etc/ssh/known_hosts",
+	})
+
+	env, err := auth.Env()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(env) != 1 || !strings.HasPrefix(env[0], "GIT_SSH_COMMAND=ssh ") {
+		t.Fatalf("Expected only GIT_SSH_COMMAND to be set, got %v", env)
+	}
+	for _, expected := range []string{"-i '/home/user/.ssh/deploy key'", "UserKnownHostsFile='/etc/ssh/known_hosts'", "StrictHostKeyChecking=yes"} {
+		if !strings.Contains(env[0], expected) {
+			t.Errorf("Expected %s to contain %s", env[0], expected)
+		}
+	}
+	if strings.Contains(env[0], "token") {
+		t.Errorf("Token leaked into %s", env[0])
+	}
+}
+
+func TestResetRepoSSHRelativePaths(t *testing.T) {
+	cleanup := setupTestEnv(t)
+	defer cleanup()
+
+	bare := setupBareRepo(t, "main")
+	for _, file := range []string{"deploy_key", "known_hosts"} {
+		if err := os.WriteFile(file, []byte("test"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A fake ssh that fails like ssh would when the key or known hosts file
+	// cannot be read, then runs the git command locally
+	bin := t.TempDir()
+	ssh := "#!/bin/sh\n" +
+		"while [ $# -gt 1 ]; do\n" +
+		"  case \"$1\" in\n" +
+		"  -i) [ -r \"$2\" ] || { echo \"Warning: Identity file $2 not accessible\" >&2; exit 255; }; shift ;;\n" +
+		"  -o) case \"$2\" in UserKnownHostsFile=*) [ -r \"${2#*=}\" ] || { echo \"Known hosts file ${2#*=} not found\" >&2; exit 255; } ;; esac; shift ;;\n" +
+		"  esac\n" +
+		"  shift\n" +
+		"done\n" +
+		"exec sh -c \"git ${1#git-}\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "ssh"), []byte(ssh), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	repoInfo := RepoInfo{
+		Provider:   GitRemote,
+		FullPath:   "ssh://git.example.com" + bare,
+		RepoName:   "repo",
+		Transport:  TransportSSH,
+		SSHKey:     "deploy_key",
+		KnownHosts: "./known_hosts",
+	}
+
+	if err := ResetRepo(repoInfo, "fresh start"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if count := git(t, bare, "rev-list", "--count", "main"); count != "1" {
+		t.Errorf("Expected 1 commit on main, got %s", count)
+	}
+}
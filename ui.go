@@ -1,6 +1,9 @@
 package main
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -175,18 +178,60 @@ func PromptCommitMessage() (string, error) {
 	return model.TextInput.Value(), nil
 }
 
-func PromptConfirmation(dryRun bool) (bool, error) {
+// describeBranch names the branch being reset, which is only known before the
+// clone when --branch is given
+func describeBranch(branch string) string {
+	if branch == "" {
+		return "the default branch"
+	}
+	return fmt.Sprintf("branch '%s'", branch)
+}
+
+// describeCommits names the commits that will be squashed, depending on how
+// much recent history is kept
+func describeCommits(branch string, keepLast int, before time.Time) string {
+	switch {
+	case keepLast > 0:
+		return fmt.Sprintf("all but the last %d commits on %s", keepLast, describeBranch(branch))
+	case !before.IsZero():
+		return fmt.Sprintf("all commits before %s on %s", formatCutoff(before), describeBranch(branch))
+	default:
+		return "all commits on " + describeBranch(branch)
+	}
+}
+
+// PromptConfirmation asks before squashing commits, as described by describeCommits
+func PromptConfirmation(dryRun bool, commits string) (bool, error) {
 	var question string
 	if dryRun {
-		question = "GoresetIT will simulate squashing all commits on main branch (DRY RUN).\n" +
+		question = "GoresetIT will simulate squashing " + commits + " (DRY RUN).\n" +
 			"This operation will perform all local operations but won't push any changes.\n" +
 			"Are you sure you want to continue?"
 	} else {
-		question = "GoresetIT will squash all commits on main branch.\n" +
+		question = "GoresetIT will squash " + commits + ".\n" +
 			"THIS IS A DESTRUCTIVE OPERATION AND CANNOT BE UNDONE!\n" +
 			"Are you sure you want to continue?"
 	}
 
+	return confirm(question)
+}
+
+// PromptRestoreConfirmation asks before force-pushing a backup bundle over the remote
+func PromptRestoreConfirmation(dryRun bool, bundle string) (bool, error) {
+	var question string
+	if dryRun {
+		question = "GoresetIT will simulate restoring all branches, tags and notes from " + bundle + " (DRY RUN).\n" +
+			"Are you sure you want to continue?"
+	} else {
+		question = "GoresetIT will force-push all branches, tags and notes from " + bundle + ".\n" +
+			"Remote refs that also exist in the bundle will be overwritten.\n" +
+			"Are you sure you want to continue?"
+	}
+	return confirm(question)
+}
+
+// confirm shows question and waits for a yes or no answer
+func confirm(question string) (bool, error) {
 	model := ConfirmModel{
 		Question: question,
 	}